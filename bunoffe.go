@@ -6,10 +6,24 @@ package bunoffe
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/uptrace/bun"
 )
 
+var (
+	// ErrEmptyCondition is returned by the Bunoffe methods that refuse
+	// to run without a condition, such as DeleteWhere.
+	ErrEmptyCondition = errors.New("bunoffe: empty condition")
+
+	// ErrInvalidPage is returned by ScanPage when the limit isn't
+	// positive or the offset is negative.
+	ErrInvalidPage = errors.New("bunoffe: invalid page")
+)
+
 type (
 	// Executor is the interface that wraps the methods of a query
 	// executor type. Bun's queries can be executed with one of the
@@ -25,6 +39,10 @@ type (
 		Exec(context.Context, ExecQuery, ...any) (sql.Result, error)
 		Scan(context.Context, ScanQuery, ...any) error
 		Exists(context.Context, ExistsQuery) (bool, error)
+		Count(context.Context, CountQuery) (int, error)
+		ScanAndCount(context.Context, ScanAndCountQuery, ...any) (int, error)
+		ScanRaw(context.Context, RawQuery, ...any) error
+		ExecRaw(context.Context, RawQuery) (sql.Result, error)
 	}
 
 	// ExecQuery is the interface that wraps the method Exec. Every
@@ -55,10 +73,80 @@ type (
 		GetModel() bun.Model
 	}
 
+	// CountQuery is the interface that wraps the method Count.
+	//
+	// Besides de Count method, the GetModel method is required for
+	// the MockQueryExecutor.
+	CountQuery interface {
+		Count(context.Context) (int, error)
+		GetModel() bun.Model
+	}
+
+	// ScanAndCountQuery is the interface that wraps the method
+	// ScanAndCount.
+	//
+	// Besides de ScanAndCount method, the GetModel method is required
+	// for the MockQueryExecutor.
+	ScanAndCountQuery interface {
+		ScanAndCount(context.Context, ...any) (int, error)
+		GetModel() bun.Model
+	}
+
+	// RawQuery is the interface that wraps the methods Scan and Exec
+	// of a query created with bun's NewRaw.
+	//
+	// Besides de Scan and Exec methods, the GetModel method is required
+	// for the MockQueryExecutor.
+	RawQuery interface {
+		Scan(context.Context, ...any) error
+		Exec(context.Context, ...any) (sql.Result, error)
+		GetModel() bun.Model
+	}
+
 	// QueryRealizer is the type of a Executor that executes the queries
 	// that are passed to one of its methods. Using the realizer has the
-	// same effect of executing a bun query directly.
-	QueryRealizer struct{}
+	// same effect of executing a bun query directly, except that, if the
+	// context is already done, its error is returned without running the
+	// query. The zero value is ready to use; NewQueryRealizer creates a
+	// realizer with hooks.
+	QueryRealizer struct {
+		beforeHook func(ctx context.Context, q any)
+		afterHook  func(ctx context.Context, r sql.Result, err error)
+	}
+
+	// QueryRealizerOption configures a QueryRealizer created by
+	// NewQueryRealizer.
+	QueryRealizerOption func(*QueryRealizer)
+
+	// SelectOptions describes the select query built by Bunoffe.Select.
+	// Zero fields are left out of the query.
+	SelectOptions struct {
+		// Columns are the selected columns. All of them by default.
+		Columns []string
+
+		// Where is the condition and Args its arguments.
+		Where string
+		Args  []any
+
+		// GroupBy is a GROUP BY expression.
+		GroupBy string
+
+		// OrderBy is an ORDER BY expression, such as "name ASC, id DESC".
+		OrderBy string
+
+		Limit  int
+		Offset int
+
+		// ForUpdate locks the selected rows with FOR UPDATE.
+		ForUpdate bool
+	}
+
+	// Cond is a condition of a WHERE clause and its arguments, e.g.
+	// Cond{Expr: "id = ?", Args: []any{5}}.
+	Cond struct {
+		Expr string
+		Args []any
+	}
 
 	// Bunoffe is similar to a repository in some ORMs: a set of commonly
 	// used queries.
@@ -68,6 +156,34 @@ type (
 	}
 )
 
+// NewQueryRealizer creates a QueryRealizer configured by opts.
+func NewQueryRealizer(opts ...QueryRealizerOption) QueryRealizer {
+	var r QueryRealizer
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// WithBeforeHook makes the realizer call f with the query right before
+// any of its Executor methods runs it, e.g. to start a tracing span.
+func WithBeforeHook(f func(ctx context.Context, q any)) QueryRealizerOption {
+	return func(r *QueryRealizer) {
+		r.beforeHook = f
+	}
+}
+
+// WithAfterHook makes the realizer call f with the outcome of the query
+// right after any of its Executor methods runs it. The sql.Result is
+// nil, except for Exec and ExecRaw.
+func WithAfterHook(
+	f func(ctx context.Context, r sql.Result, err error),
+) QueryRealizerOption {
+	return func(r *QueryRealizer) {
+		r.afterHook = f
+	}
+}
+
 // Exec executes a bun query that has the Exec method. Calling:
 //
 //	executor.Exec(ctx, query, args...)
@@ -75,12 +191,18 @@ type (
 // is equivalent to running
 //
 //	query.Exec(ctx, args...)
-func (QueryRealizer) Exec(
+func (r QueryRealizer) Exec(
 	ctx context.Context,
 	q ExecQuery,
 	args ...any,
 ) (sql.Result, error) {
-	return q.Exec(ctx, args...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.before(ctx, q)
+	res, err := q.Exec(ctx, args...)
+	r.after(ctx, res, err)
+	return res, err
 }
 
 // Scan executes a bun query that has the Scan method. Calling:
@@ -90,8 +212,14 @@ func (QueryRealizer) Exec(
 // is equivalent to running
 //
 //	query.Scan(ctx, args...)
-func (QueryRealizer) Scan(ctx context.Context, q ScanQuery, args ...any) error {
-	return q.Scan(ctx, args...)
+func (r QueryRealizer) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.before(ctx, q)
+	err := q.Scan(ctx, args...)
+	r.after(ctx, nil, err)
+	return err
 }
 
 // Exists executes a bun query that has the Exists method. Calling:
@@ -101,8 +229,100 @@ func (QueryRealizer) Scan(ctx context.Context, q ScanQuery, args ...any) error {
 // is equivalent to running
 //
 //	query.Exists(ctx)
-func (QueryRealizer) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
-	return q.Exists(ctx)
+func (r QueryRealizer) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.before(ctx, q)
+	exists, err := q.Exists(ctx)
+	r.after(ctx, nil, err)
+	return exists, err
+}
+
+// Count executes a bun query that has the Count method. Calling:
+//
+//	executor.Count(ctx, query)
+//
+// is equivalent to running
+//
+//	query.Count(ctx)
+func (r QueryRealizer) Count(ctx context.Context, q CountQuery) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.before(ctx, q)
+	count, err := q.Count(ctx)
+	r.after(ctx, nil, err)
+	return count, err
+}
+
+// ScanAndCount executes a bun query that has the ScanAndCount method.
+// Calling:
+//
+//	executor.ScanAndCount(ctx, query, args...)
+//
+// is equivalent to running
+//
+//	query.ScanAndCount(ctx, args...)
+func (r QueryRealizer) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.before(ctx, q)
+	count, err := q.ScanAndCount(ctx, args...)
+	r.after(ctx, nil, err)
+	return count, err
+}
+
+// ScanRaw executes a raw bun query, scanning the result into dest.
+// Calling:
+//
+//	executor.ScanRaw(ctx, db.NewRaw("SELECT ..."), dest...)
+//
+// is equivalent to running
+//
+//	db.NewRaw("SELECT ...").Scan(ctx, dest...)
+func (r QueryRealizer) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.before(ctx, q)
+	err := q.Scan(ctx, dest...)
+	r.after(ctx, nil, err)
+	return err
+}
+
+// ExecRaw executes a raw bun query. Calling:
+//
+//	executor.ExecRaw(ctx, db.NewRaw("DELETE ..."))
+//
+// is equivalent to running
+//
+//	db.NewRaw("DELETE ...").Exec(ctx)
+func (r QueryRealizer) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.before(ctx, q)
+	res, err := q.Exec(ctx)
+	r.after(ctx, res, err)
+	return res, err
+}
+
+func (r QueryRealizer) before(ctx context.Context, q any) {
+	if r.beforeHook != nil {
+		r.beforeHook(ctx, q)
+	}
+}
+
+func (r QueryRealizer) after(ctx context.Context, res sql.Result, err error) {
+	if r.afterHook != nil {
+		r.afterHook(ctx, res, err)
+	}
 }
 
 func (b Bunoffe) ScanWhere(
@@ -119,6 +339,154 @@ func (b Bunoffe) ScanWhere(
 	)
 }
 
+// ScanWhereOr is like ScanWhere, but scans the rows that satisfy any
+// of the conditions, OR'd together. It returns ErrEmptyCondition if
+// there's no condition.
+func (b Bunoffe) ScanWhereOr(ctx context.Context, model any, conds []Cond) error {
+	if len(conds) == 0 {
+		return ErrEmptyCondition
+	}
+
+	q := b.DB.NewSelect().
+		Model(model).
+		Where(conds[0].Expr, conds[0].Args...)
+	for _, c := range conds[1:] {
+		q = q.WhereOr(c.Expr, c.Args...)
+	}
+	return b.X.Scan(ctx, q)
+}
+
+// ScanWithRelations is like ScanWhere, but also loads the given
+// relations of the model, e.g. []string{"Author"}, as if by calling
+// `.Relation(r)` for each one. With no relations, it's ScanWhere.
+func (b Bunoffe) ScanWithRelations(
+	ctx context.Context,
+	model any,
+	relations []string,
+	cond string,
+	condArgs ...any,
+) error {
+	q := b.DB.NewSelect().Model(model)
+	for _, r := range relations {
+		q = q.Relation(r)
+	}
+	return b.X.Scan(ctx, q.Where(cond, condArgs...))
+}
+
+// ScanForUpdate is like ScanWhere, but locks the selected rows with
+// FOR UPDATE until the transaction ends, for read-modify-write flows.
+func (b Bunoffe) ScanForUpdate(
+	ctx context.Context,
+	model any,
+	cond string,
+	condArgs ...any,
+) error {
+	return b.scanFor(ctx, "UPDATE", model, cond, condArgs)
+}
+
+// ScanForUpdateSkipLocked is like ScanForUpdate, but skips the rows
+// that are already locked, with FOR UPDATE SKIP LOCKED, so concurrent
+// workers of a job queue don't pick the same rows.
+func (b Bunoffe) ScanForUpdateSkipLocked(
+	ctx context.Context,
+	model any,
+	cond string,
+	condArgs ...any,
+) error {
+	return b.scanFor(ctx, "UPDATE SKIP LOCKED", model, cond, condArgs)
+}
+
+func (b Bunoffe) scanFor(
+	ctx context.Context,
+	lock string,
+	model any,
+	cond string,
+	condArgs []any,
+) error {
+	return b.X.Scan(
+		ctx,
+		b.DB.NewSelect().
+			Model(model).
+			Where(cond, condArgs...).
+			For(lock),
+	)
+}
+
+// ScanColumns is like ScanWhere, but selects only the given columns,
+// e.g. to leave out large columns that aren't needed. The other fields
+// of the model are left untouched.
+func (b Bunoffe) ScanColumns(
+	ctx context.Context,
+	model any,
+	columns []string,
+	cond string,
+	condArgs ...any,
+) error {
+	return b.X.Scan(
+		ctx,
+		b.DB.NewSelect().
+			Model(model).
+			Column(columns...).
+			Where(cond, condArgs...),
+	)
+}
+
+// ScanWhereOrdered works like ScanWhere, but sorts the rows by order,
+// which is an ORDER BY expression. Multiple columns are separated by
+// commas, as in "name ASC, id DESC".
+func (b Bunoffe) ScanWhereOrdered(
+	ctx context.Context,
+	model any,
+	order string,
+	cond string,
+	condArgs ...any,
+) error {
+	return b.X.Scan(
+		ctx,
+		b.DB.NewSelect().
+			Model(model).
+			Where(cond, condArgs...).
+			OrderExpr(order),
+	)
+}
+
+// ScanWhereWithDeleted works like ScanWhere, but includes the rows
+// soft deleted with bun's soft delete support.
+func (b Bunoffe) ScanWhereWithDeleted(
+	ctx context.Context,
+	model any,
+	cond string,
+	condArgs ...any,
+) error {
+	return b.X.Scan(
+		ctx,
+		b.DB.NewSelect().
+			Model(model).
+			WhereAllWithDeleted().
+			Where(cond, condArgs...),
+	)
+}
+
+// ScanAggregate scans the rows grouped by groupBy, a GROUP BY
+// expression, and filtered by having, a HAVING condition. If having is
+// empty, the HAVING clause is omitted. The model usually has the
+// aggregate columns, e.g. `bun:"count,scanonly"`.
+func (b Bunoffe) ScanAggregate(
+	ctx context.Context,
+	model any,
+	groupBy string,
+	having string,
+	havingArgs ...any,
+) error {
+	q := b.DB.NewSelect().
+		Model(model).
+		GroupExpr(groupBy)
+	if having != "" {
+		q = q.Having(having, havingArgs...)
+	}
+	return b.X.Scan(ctx, q)
+}
+
 func (b Bunoffe) ScanWherePK(ctx context.Context, model any, pks ...string) error {
 	return b.X.Scan(
 		ctx,
@@ -128,6 +496,55 @@ func (b Bunoffe) ScanWherePK(ctx context.Context, model any, pks ...string) erro
 	)
 }
 
+// Get scans the row identified by the model's primary key into the
+// model, like ScanWherePK. It returns false if there's no such row,
+// instead of sql.ErrNoRows. To exercise the not found path in tests,
+// queue a MockScanOperation{Error: sql.ErrNoRows}.
+func (b Bunoffe) Get(ctx context.Context, model any, pks ...string) (bool, error) {
+	if err := b.ScanWherePK(ctx, model, pks...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Select scans the rows selected by a query built from opts into the
+// model. It's the most flexible of the select helpers. For instance:
+//
+//	err := b.Select(ctx, &users, bunoffe.SelectOptions{
+//		Where:   "age > ?",
+//		Args:    []any{18},
+//		OrderBy: "name ASC",
+//		Limit:   10,
+//	})
+func (b Bunoffe) Select(ctx context.Context, model any, opts SelectOptions) error {
+	q := b.DB.NewSelect().Model(model)
+	if len(opts.Columns) > 0 {
+		q = q.Column(opts.Columns...)
+	}
+	if opts.Where != "" {
+		q = q.Where(opts.Where, opts.Args...)
+	}
+	if opts.GroupBy != "" {
+		q = q.GroupExpr(opts.GroupBy)
+	}
+	if opts.OrderBy != "" {
+		q = q.OrderExpr(opts.OrderBy)
+	}
+	if opts.Limit != 0 {
+		q = q.Limit(opts.Limit)
+	}
+	if opts.Offset != 0 {
+		q = q.Offset(opts.Offset)
+	}
+	if opts.ForUpdate {
+		q = q.For("UPDATE")
+	}
+	return b.X.Scan(ctx, q)
+}
+
 func (b Bunoffe) SelectWhere(
 	ctx context.Context,
 	model any,
@@ -155,6 +572,44 @@ func (b Bunoffe) SelectWherePK(
 	)
 }
 
+func (b Bunoffe) SelectPage(
+	ctx context.Context,
+	model any,
+	limit, offset int,
+	cond string,
+	condArgs ...any,
+) error {
+	return b.X.Scan(
+		ctx,
+		b.DB.NewSelect().
+			Model(model).
+			Where(cond, condArgs...).
+			Limit(limit).
+			Offset(offset),
+	)
+}
+
+// ScanPage works like SelectPage, but first checks that limit is
+// positive and offset is non-negative, returning ErrInvalidPage
+// otherwise.
+func (b Bunoffe) ScanPage(
+	ctx context.Context,
+	models any,
+	limit, offset int,
+	cond string,
+	condArgs ...any,
+) error {
+	if limit <= 0 || offset < 0 {
+		return fmt.Errorf(
+			"%w: limit %v and offset %v",
+			ErrInvalidPage,
+			limit,
+			offset,
+		)
+	}
+	return b.SelectPage(ctx, models, limit, offset, cond, condArgs...)
+}
+
 func (b Bunoffe) ExistsWhere(
 	ctx context.Context,
 	model any,
@@ -182,14 +637,177 @@ func (b Bunoffe) ExistsWherePK(
 	)
 }
 
+// CountWhere counts the rows that satisfy the condition. The count is an
+// int, like bun's, which is 64 bits wide on 64-bit platforms.
+func (b Bunoffe) CountWhere(
+	ctx context.Context,
+	model any,
+	cond string,
+	condArgs ...any,
+) (int, error) {
+	return b.X.Count(
+		ctx,
+		b.DB.NewSelect().
+			Model(model).
+			Where(cond, condArgs...),
+	)
+}
+
+// CountAll counts every row of the model's table.
+func (b Bunoffe) CountAll(ctx context.Context, model any) (int, error) {
+	return b.X.Count(ctx, b.DB.NewSelect().Model(model))
+}
+
+// ExistsByPK reports whether there's a row whose primary key equals the
+// model's. The primary key is the one bun derives from the model's
+// struct tags (`bun:",pk"`). It's equivalent to ExistsWherePK without
+// pks, which otherwise names the columns that identify the row.
+func (b Bunoffe) ExistsByPK(ctx context.Context, model any) (bool, error) {
+	return b.ExistsWherePK(ctx, model)
+}
+
 func (b Bunoffe) Insert(ctx context.Context, model any) (sql.Result, error) {
 	return b.X.Exec(ctx, b.DB.NewInsert().Model(model))
 }
 
+// InsertIfNotExists inserts the model unless a row satisfies the
+// condition, reporting whether it was inserted. The check and the
+// insert are separate queries, so it isn't atomic: run it in a
+// transaction, or rely on a unique constraint, if concurrent inserts
+// are possible.
+func (b Bunoffe) InsertIfNotExists(
+	ctx context.Context,
+	model any,
+	cond string,
+	condArgs ...any,
+) (bool, sql.Result, error) {
+	exists, err := b.ExistsWhere(ctx, model, cond, condArgs...)
+	if err != nil || exists {
+		return false, nil, err
+	}
+
+	res, err := b.Insert(ctx, model)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, res, nil
+}
+
+// Upsert inserts the model or, on conflict, updates it. conflict is the
+// ON clause, and each set is a SET clause of the update. For instance:
+//
+//	b.Upsert(ctx, &u, "CONFLICT (id) DO UPDATE", "name = EXCLUDED.name")
+func (b Bunoffe) Upsert(
+	ctx context.Context,
+	model any,
+	conflict string,
+	set ...string,
+) (sql.Result, error) {
+	q := b.DB.NewInsert().
+		Model(model).
+		On(conflict)
+	for _, s := range set {
+		q = q.Set(s)
+	}
+	return b.X.Exec(ctx, q)
+}
+
+// InsertIgnore inserts the model unless it conflicts with an existing
+// row, with ON CONFLICT DO NOTHING, e.g. for idempotent seeding. The
+// result's RowsAffected is 0 when the insert was skipped.
+func (b Bunoffe) InsertIgnore(ctx context.Context, model any) (sql.Result, error) {
+	return b.X.Exec(ctx, b.DB.NewInsert().Model(model).On("CONFLICT DO NOTHING"))
+}
+
+// InsertReturning inserts the model and assigns the given columns, as
+// returned by the database, back to it. The columns are joined into a
+// single RETURNING clause; with no columns, it's RETURNING *, so every
+// column is assigned back. In tests, the returned values can be
+// provided with MockExecOperation.Returning.
+func (b Bunoffe) InsertReturning(
+	ctx context.Context,
+	model any,
+	columns ...string,
+) (sql.Result, error) {
+	returning := "*"
+	if len(columns) > 0 {
+		returning = strings.Join(columns, ", ")
+	}
+	return b.X.Exec(ctx, b.DB.NewInsert().Model(model).Returning(returning))
+}
+
+// BulkInsert inserts every model of a slice in a single query. models
+// must be a slice or a pointer to a slice, otherwise an error is
+// returned.
+func (b Bunoffe) BulkInsert(ctx context.Context, models any) (sql.Result, error) {
+	v := reflect.ValueOf(models)
+	switch {
+	case v.Kind() == reflect.Slice:
+		// bun only accepts pointers to slices as models.
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		models = p.Interface()
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Slice:
+	default:
+		return nil, fmt.Errorf(
+			"bunoffe: BulkInsert expects a slice or a pointer to a slice, but got %T",
+			models,
+		)
+	}
+
+	return b.X.Exec(ctx, b.DB.NewInsert().Model(models))
+}
+
 func (b Bunoffe) Update(ctx context.Context, model any) (sql.Result, error) {
 	return b.X.Exec(ctx, b.DB.NewUpdate().Model(model))
 }
 
+// UpdateWhere updates the columns in set of the rows that satisfy the
+// condition. setArgs are bound to the placeholders of set, and condArgs
+// to the ones of cond. Named placeholders, such as ?name, take no args.
+// For instance:
+//
+//	b.UpdateWhere(ctx, &u, "name = ?", []any{"John"}, "id = ?", 5)
+//
+// runs
+//
+//	UPDATE ... SET name = 'John' WHERE (id = 5)
+func (b Bunoffe) UpdateWhere(
+	ctx context.Context,
+	model any,
+	set string,
+	setArgs []any,
+	cond string,
+	condArgs ...any,
+) (sql.Result, error) {
+	return b.X.Exec(
+		ctx,
+		b.DB.NewUpdate().
+			Model(model).
+			Set(set, setArgs...).
+			Where(cond, condArgs...),
+	)
+}
+
+// UpdateColumns updates only the given columns of the rows that satisfy
+// the condition, using the values of the model. The other columns are
+// left untouched.
+func (b Bunoffe) UpdateColumns(
+	ctx context.Context,
+	model any,
+	columns []string,
+	cond string,
+	condArgs ...any,
+) (sql.Result, error) {
+	return b.X.Exec(
+		ctx,
+		b.DB.NewUpdate().
+			Model(model).
+			Column(columns...).
+			Where(cond, condArgs...),
+	)
+}
+
 func (b Bunoffe) DeleteWherePK(
 	ctx context.Context,
 	model any,
@@ -202,3 +820,241 @@ func (b Bunoffe) DeleteWherePK(
 			WherePK(pks...),
 	)
 }
+
+// InsertAffected is like Insert, but returns the number of rows
+// affected instead of the sql.Result. A nil result counts as 0.
+func (b Bunoffe) InsertAffected(ctx context.Context, model any) (int64, error) {
+	return rowsAffected(b.Insert(ctx, model))
+}
+
+// UpdateAffected is like Update, but returns the number of rows
+// affected instead of the sql.Result. A nil result counts as 0.
+func (b Bunoffe) UpdateAffected(ctx context.Context, model any) (int64, error) {
+	return rowsAffected(b.Update(ctx, model))
+}
+
+// DeleteAffected is like DeleteWherePK, but returns the number of rows
+// affected instead of the sql.Result. A nil result counts as 0.
+func (b Bunoffe) DeleteAffected(
+	ctx context.Context,
+	model any,
+	pks ...string,
+) (int64, error) {
+	return rowsAffected(b.DeleteWherePK(ctx, model, pks...))
+}
+
+func rowsAffected(res sql.Result, err error) (int64, error) {
+	if err != nil || res == nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ScanOne is a type safe version of ScanWhere. It allocates a T,
+// scans the row that satisfies the condition into it, and returns it.
+// For instance:
+//
+//	u, err := bunoffe.ScanOne[User](ctx, b, "id = ?", 5)
+//
+// If the scan fails, the zero T and the error are returned. That
+// includes sql.ErrNoRows, which is returned unchanged.
+func ScanOne[T any](
+	ctx context.Context,
+	b Bunoffe,
+	cond string,
+	condArgs ...any,
+) (T, error) {
+	var m T
+	if err := b.ScanWhere(ctx, &m, cond, condArgs...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return m, nil
+}
+
+// ScanMany is a type safe version of ScanWhere for multiple rows. It
+// allocates a []T, scans the rows that satisfy the condition into it,
+// and returns it. For instance:
+//
+//	us, err := bunoffe.ScanMany[User](ctx, b, "age > ?", 18)
+//
+// If the scan fails, a nil slice and the error are returned.
+func ScanMany[T any](
+	ctx context.Context,
+	b Bunoffe,
+	cond string,
+	condArgs ...any,
+) ([]T, error) {
+	var ms []T
+	if err := b.ScanWhere(ctx, &ms, cond, condArgs...); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// SoftDelete marks the row identified by the model's primary key as
+// deleted, for models that support bun's soft deletes. It's the same
+// query as DeleteWherePK, which bun turns into an UPDATE of the soft
+// delete column. Use DeleteForced to actually delete the row.
+func (b Bunoffe) SoftDelete(
+	ctx context.Context,
+	model any,
+	pks ...string,
+) (sql.Result, error) {
+	return b.DeleteWherePK(ctx, model, pks...)
+}
+
+// DeleteForced works like DeleteWherePK, but deletes the row even if
+// the model supports soft deletes, instead of marking it as deleted.
+func (b Bunoffe) DeleteForced(
+	ctx context.Context,
+	model any,
+	pks ...string,
+) (sql.Result, error) {
+	return b.X.Exec(
+		ctx,
+		b.DB.NewDelete().
+			Model(model).
+			WherePK(pks...).
+			ForceDelete(),
+	)
+}
+
+// Truncate deletes every row of the model's table. Truncate queries
+// are ExecQuerys, so they're mocked with a MockExecOperation.
+func (b Bunoffe) Truncate(ctx context.Context, model any) (sql.Result, error) {
+	return b.X.Exec(ctx, b.DB.NewTruncateTable().Model(model))
+}
+
+// TruncateCascade is like Truncate, but also truncates the tables that
+// reference the model's table through foreign keys, with CASCADE, on
+// the dialects that support it.
+func (b Bunoffe) TruncateCascade(ctx context.Context, model any) (sql.Result, error) {
+	return b.X.Exec(ctx, b.DB.NewTruncateTable().Model(model).Cascade())
+}
+
+// DeleteWhere deletes the rows that satisfy the condition. To avoid
+// deleting every row of the table by mistake, it returns
+// ErrEmptyCondition if cond is empty.
+func (b Bunoffe) DeleteWhere(
+	ctx context.Context,
+	model any,
+	cond string,
+	condArgs ...any,
+) (sql.Result, error) {
+	if strings.TrimSpace(cond) == "" {
+		return nil, ErrEmptyCondition
+	}
+
+	return b.X.Exec(
+		ctx,
+		b.DB.NewDelete().
+			Model(model).
+			Where(cond, condArgs...),
+	)
+}
+
+// FindByPK scans the row whose primary key is pk into a T and returns
+// it. T must be a struct with a single primary key. If there's no such
+// row, it returns the zero T and false, instead of sql.ErrNoRows.
+// For instance:
+//
+//	u, found, err := bunoffe.FindByPK[User](ctx, b, 5)
+func FindByPK[T any](ctx context.Context, b Bunoffe, pk any) (T, bool, error) {
+	var zero T
+	m, err := newWithPK[T](b.DB, pk)
+	if err != nil {
+		return zero, false, err
+	}
+
+	if err := b.ScanWherePK(ctx, m); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero, false, nil
+		}
+		return zero, false, err
+	}
+	return *m, true, nil
+}
+
+// ExistsPK reports whether there's a row whose primary key is pk,
+// without the need of allocating a T beforehand. T must be a struct
+// with a single primary key, i.e. a single field tagged `bun:",pk"`,
+// which is the column WherePK uses. For instance:
+//
+//	exists, err := bunoffe.ExistsPK[User](ctx, b, 5)
+func ExistsPK[T any](ctx context.Context, b Bunoffe, pk any) (bool, error) {
+	m, err := newWithPK[T](b.DB, pk)
+	if err != nil {
+		return false, err
+	}
+	return b.ExistsWherePK(ctx, m)
+}
+
+// GetOrCreate scans the row that satisfies the condition into a T and
+// returns it. If there's no such row, it inserts create instead and
+// returns it, as assigned by the insert, with created set to true. For
+// instance:
+//
+//	u, created, err := bunoffe.GetOrCreate(ctx, b, "email = ?", args, User{Email: email})
+//
+// In tests, mock it with a MockScanOperation that returns sql.ErrNoRows
+// followed by a MockExecOperation.
+func GetOrCreate[T any](
+	ctx context.Context,
+	b Bunoffe,
+	cond string,
+	args []any,
+	create T,
+) (T, bool, error) {
+	var m T
+	err := b.ScanWhere(ctx, &m, cond, args...)
+	if err == nil {
+		return m, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		var zero T
+		return zero, false, err
+	}
+
+	if _, err := b.Insert(ctx, &create); err != nil {
+		var zero T
+		return zero, false, err
+	}
+	return create, true, nil
+}
+
+// newWithPK allocates a T and sets its primary keys to the values of
+// keys, in the order they're declared. T must be a struct with as many
+// primary keys as keys.
+func newWithPK[T any](db bun.IDB, keys ...any) (*T, error) {
+	m := new(T)
+	v := reflect.ValueOf(m).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("bunoffe: %v isn't a struct", v.Type())
+	}
+
+	table := db.Dialect().Tables().Get(v.Type())
+	if len(table.PKs) != len(keys) {
+		return nil, fmt.Errorf(
+			"bunoffe: %v has %v primary keys, but %v were given",
+			v.Type(),
+			len(table.PKs),
+			len(keys),
+		)
+	}
+
+	for i, key := range keys {
+		fv := table.PKs[i].Value(v)
+		pv := reflect.ValueOf(key)
+		if !pv.IsValid() || !convertible(pv.Type(), fv.Type()) {
+			return nil, fmt.Errorf(
+				"bunoffe: %T can't be used as the primary key %v of %v",
+				key,
+				table.PKs[i].Name,
+				v.Type(),
+			)
+		}
+		fv.Set(pv.Convert(fv.Type()))
+	}
+	return m, nil
+}
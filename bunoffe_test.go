@@ -0,0 +1,1108 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID   int64 `bun:",pk"`
+	Name string
+}
+
+type post struct {
+	ID        int64     `bun:",pk"`
+	DeletedAt time.Time `bun:",soft_delete,nullzero"`
+}
+
+// lastSQL runs f with a Bunoffe backed by a LoggingRealizer and returns
+// the SQL of the last query it executed. The queries fail because the
+// mocked database has no expectations, but they're still rendered.
+func lastSQL(t *testing.T, f func(b Bunoffe)) string {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	var query string
+	r := LoggingRealizer{
+		Log: func(q string, _ time.Duration, _ error) {
+			query = q
+		},
+	}
+	f(Bunoffe{X: r, DB: db})
+	return query
+}
+
+func TestScanOne(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e = errors.New("an error")
+		m = model{String: "Hello, world!", Int: 33}
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+			MockScanOperation{Error: e},
+			MockScanOperation{Error: sql.ErrNoRows},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	n, err := ScanOne[model](ctx, b, "int = ?", 33)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	n, err = ScanOne[model](ctx, b, "int = ?", 33)
+	assert.Equal(t, e, err)
+	assert.Equal(t, model{}, n)
+
+	n, err = ScanOne[model](ctx, b, "int = ?", 33)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Equal(t, model{}, n)
+}
+
+func TestScanMany(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e = errors.New("an error")
+		m = []model{{String: "Hello", Int: 1}, {String: "World", Int: 2}}
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+			MockScanOperation{Model: &[]model{}},
+			MockScanOperation{Error: e},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	n, err := ScanMany[model](ctx, b, "int > ?", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	n, err = ScanMany[model](ctx, b, "int > ?", 10)
+	assert.Nil(t, err)
+	assert.Empty(t, n)
+
+	n, err = ScanMany[model](ctx, b, "int > ?", 0)
+	assert.Equal(t, e, err)
+	assert.Nil(t, n)
+}
+
+func TestCountWhere(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	e := errors.New("an error")
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockCountOperation{Count: 7},
+			MockCountOperation{Count: 0},
+			MockCountOperation{Count: math.MaxInt},
+			MockCountOperation{Error: e},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	var m model
+	c, err := b.CountWhere(ctx, &m, "int > ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, c)
+
+	c, err = b.CountWhere(ctx, &m, "int > ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, c)
+
+	c, err = b.CountWhere(ctx, &m, "int > ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, math.MaxInt, c)
+
+	c, err = b.CountWhere(ctx, &m, "int > ?", 1)
+	assert.Equal(t, e, err)
+	assert.Equal(t, 0, c)
+}
+
+func TestCountAll(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockCountOperation{Count: 12},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	c, err := b.CountAll(ctx, (*model)(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, 12, c)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.CountAll(ctx, (*model)(nil))
+	})
+	assert.Contains(t, query, `FROM "models"`)
+	assert.NotContains(t, query, "WHERE")
+}
+
+func TestAffected(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e  = errors.New("an error")
+		re = errors.New("rows affected error")
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Result: NewResult(1, 1)},
+			MockExecOperation{Result: NewResult(0, 3)},
+			MockExecOperation{},
+			MockExecOperation{Error: e},
+			MockExecOperation{Result: MockQueryResult{RowsAffectedError: re}},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	u := user{ID: 1, Name: "John"}
+
+	n, err := b.InsertAffected(ctx, &u)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = b.UpdateAffected(ctx, &u)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+
+	n, err = b.DeleteAffected(ctx, &u)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+
+	n, err = b.DeleteAffected(ctx, &u)
+	assert.Equal(t, e, err)
+	assert.Equal(t, int64(0), n)
+
+	_, err = b.UpdateAffected(ctx, &u)
+	assert.Equal(t, re, err)
+	assert.Equal(t, []string{"Exec", "Exec", "Exec", "Exec", "Exec"}, ex.CallLog())
+}
+
+func TestInsertIfNotExists(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e      = errors.New("an error")
+		result = NewResult(1, 1)
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: false},
+			MockExecOperation{Result: result},
+			MockExistsOperation{Exists: true},
+			MockExistsOperation{Error: e},
+			MockExistsOperation{Exists: false},
+			MockExecOperation{Error: e},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	u := user{Name: "John"}
+
+	inserted, res, err := b.InsertIfNotExists(ctx, &u, "name = ?", u.Name)
+	assert.Nil(t, err)
+	assert.True(t, inserted)
+	assert.Equal(t, result, res)
+
+	inserted, res, err = b.InsertIfNotExists(ctx, &u, "name = ?", u.Name)
+	assert.Nil(t, err)
+	assert.False(t, inserted)
+	assert.Nil(t, res)
+
+	inserted, _, err = b.InsertIfNotExists(ctx, &u, "name = ?", u.Name)
+	assert.Equal(t, e, err)
+	assert.False(t, inserted)
+
+	inserted, _, err = b.InsertIfNotExists(ctx, &u, "name = ?", u.Name)
+	assert.Equal(t, e, err)
+	assert.False(t, inserted)
+	assert.Equal(
+		t,
+		[]string{"Exists", "Exec", "Exists", "Exists", "Exists", "Exec"},
+		ex.CallLog(),
+	)
+}
+
+func TestSelectPage(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	// expected
+	m := []model{{String: "Hello", Int: 1}, {String: "World", Int: 2}}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	var n []model
+	err = b.SelectPage(context.Background(), &n, 2, 10, "int > ?", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+}
+
+func TestScanPage(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := []model{{String: "Hello", Int: 1}}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	var n []model
+	err = b.ScanPage(ctx, &n, 0, 0, "int > ?", 0)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+
+	err = b.ScanPage(ctx, &n, 10, -1, "int > ?", 0)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+
+	err = b.ScanPage(ctx, &n, 10, 0, "int > ?", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.ScanPage(ctx, &n, 10, 20, "int > ?", 0)
+	})
+	assert.Contains(t, query, `WHERE (int > 0) LIMIT 10 OFFSET 20`)
+}
+
+func TestScanWithRelations(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := article{ID: 1, AuthorID: 2, Author: &author{ID: 2, Name: "John"}}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	// results
+	var n article
+
+	err = b.ScanWithRelations(ctx, &n, []string{"Author"}, "article.id = ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.ScanWithRelations(ctx, &n, []string{"Author"}, "article.id = ?", 1)
+	})
+	assert.Contains(t, query, `LEFT JOIN "authors" AS "author"`)
+	assert.Contains(t, query, `WHERE (article.id = 1)`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		b.ScanWithRelations(ctx, &n, nil, "article.id = ?", 1)
+	})
+	assert.Equal(t, lastSQL(t, func(b Bunoffe) {
+		b.ScanWhere(ctx, &n, "article.id = ?", 1)
+	}), query)
+}
+
+func TestScanForUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m model
+		b.ScanForUpdate(ctx, &m, "int = ?", 1)
+	})
+	assert.True(t, strings.HasSuffix(query, `WHERE (int = 1) FOR UPDATE`), query)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		var m model
+		b.ScanForUpdateSkipLocked(ctx, &m, "int = ?", 1)
+	})
+	assert.True(t, strings.HasSuffix(query, `WHERE (int = 1) FOR UPDATE SKIP LOCKED`), query)
+}
+
+func TestScanWhereOr(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := []model{{String: "a", Int: 1}, {String: "b", Int: 2}}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	conds := []Cond{
+		{Expr: "string = ?", Args: []any{"a"}},
+		{Expr: "int > ?", Args: []any{1}},
+	}
+
+	// results
+	var n []model
+
+	err = b.ScanWhereOr(ctx, &n, conds)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	err = b.ScanWhereOr(ctx, &n, nil)
+	assert.ErrorIs(t, err, ErrEmptyCondition)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.ScanWhereOr(ctx, &n, conds)
+	})
+	assert.Contains(t, query, `WHERE (string = 'a') OR (int > 1)`)
+}
+
+func TestScanColumns(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m model
+		b.ScanColumns(ctx, &m, []string{"string", "int"}, "int > ?", 10)
+	})
+	assert.Contains(t, query, `SELECT "model"."string", "model"."int" FROM "models"`)
+	assert.Contains(t, query, `WHERE (int > 10)`)
+}
+
+func TestScanWhereOrdered(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m []model
+		b.ScanWhereOrdered(ctx, &m, "int DESC", "int > ?", 1)
+	})
+	assert.Contains(t, query, `WHERE (int > 1) ORDER BY int DESC`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		var m []model
+		b.ScanWhereOrdered(ctx, &m, "string ASC, int DESC", "int > ?", 1)
+	})
+	assert.Contains(t, query, `ORDER BY string ASC, int DESC`)
+}
+
+func TestUpdateWhere(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m model
+		b.UpdateWhere(ctx, &m, "string = ?", []any{"hadouken"}, "int = ?", 33)
+	})
+	assert.Contains(t, query, `SET string = 'hadouken' WHERE (int = 33)`)
+
+	// Named placeholders take no args.
+	query = lastSQL(t, func(b Bunoffe) {
+		m := model{String: "hadouken"}
+		b.UpdateWhere(ctx, &m, "string = ?string", nil, "int = ?", 33)
+	})
+	assert.Contains(t, query, `SET string = 'hadouken' WHERE (int = 33)`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		var m model
+		b.UpdateWhere(ctx, &m, "int = int + 1", nil, "string = ?", "a")
+	})
+	assert.Contains(t, query, `SET int = int + 1 WHERE (string = 'a')`)
+}
+
+func TestDeleteWhere(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	result := MockQueryResult{RowsAffectedValue: 3}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Result: result},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	var m model
+	r, err := b.DeleteWhere(ctx, &m, " ")
+	assert.ErrorIs(t, err, ErrEmptyCondition)
+	assert.Nil(t, r)
+
+	r, err = b.DeleteWhere(ctx, &m, "int < ?", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result, r)
+}
+
+func TestUpsert(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		m := model{String: "Hello", Int: 1}
+		b.Upsert(ctx, &m, "CONFLICT (int) DO UPDATE", "string = EXCLUDED.string")
+	})
+	assert.Contains(
+		t,
+		query,
+		`ON CONFLICT (int) DO UPDATE SET string = EXCLUDED.string`,
+	)
+}
+
+func TestInsertIgnore(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Result: NewResult(1, 1)},
+			MockExecOperation{Result: NewResult(0, 0)},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	m := model{String: "Hello", Int: 1}
+
+	n, err := rowsAffected(b.InsertIgnore(ctx, &m))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = rowsAffected(b.InsertIgnore(ctx, &m))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.InsertIgnore(ctx, &m)
+	})
+	assert.Contains(t, query, `ON CONFLICT DO NOTHING`)
+}
+
+func TestBulkInsert(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		m      = []model{{String: "Hello", Int: 1}, {String: "World", Int: 2}}
+		result = MockQueryResult{RowsAffectedValue: 2}
+	)
+
+	// results
+	var n, o []model
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Model: &n, Result: result},
+			MockExecOperation{Model: &o},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	r, err := b.BulkInsert(ctx, &m)
+	assert.Nil(t, err)
+	assert.Equal(t, result, r)
+	assert.Equal(t, m, n)
+
+	_, err = b.BulkInsert(ctx, m)
+	assert.Nil(t, err)
+	assert.Equal(t, m, o)
+
+	_, err = b.BulkInsert(ctx, &m[0])
+	assert.NotNil(t, err)
+}
+
+func TestBulkInsertReturning(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	// expected
+	m := []model{{String: "Hello", Int: 1}, {String: "World", Int: 2}}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Returning: &m},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	// results
+	n := []model{{String: "Hello"}, {String: "World"}}
+
+	_, err = b.BulkInsert(context.Background(), &n)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+}
+
+func TestDeleteWhereSQL(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m model
+		b.DeleteWhere(ctx, &m, "string = ?", "expired")
+	})
+	assert.Contains(t, query, `DELETE FROM "models"`)
+	assert.Contains(t, query, `WHERE (string = 'expired')`)
+}
+
+func TestUpdateColumns(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		m := model{String: "Hello", Int: 1}
+		b.UpdateColumns(ctx, &m, []string{"string"}, "int = ?", 1)
+	})
+	assert.Contains(t, query, `SET "string" = 'Hello' WHERE (int = 1)`)
+	assert.NotContains(t, query, `"int" =`)
+}
+
+func TestInsertReturning(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := model{String: "Hello", Int: 10}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Returning: &m},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	n := model{String: "Hello"}
+	_, err = b.InsertReturning(ctx, &n, "int")
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.InsertReturning(ctx, &n, "int", "string")
+	})
+	assert.Contains(t, query, `RETURNING int, string`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		b.InsertReturning(ctx, &n)
+	})
+	assert.Contains(t, query, `RETURNING *`)
+}
+
+func TestFindByPK(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e = errors.New("an error")
+		u = user{ID: 5, Name: "John"}
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &u},
+			MockScanOperation{Error: sql.ErrNoRows},
+			MockScanOperation{Error: e},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	v, found, err := FindByPK[user](ctx, b, 5)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, u, v)
+
+	v, found, err = FindByPK[user](ctx, b, 6)
+	assert.Nil(t, err)
+	assert.False(t, found)
+	assert.Equal(t, user{}, v)
+
+	_, found, err = FindByPK[user](ctx, b, 7)
+	assert.Equal(t, e, err)
+	assert.False(t, found)
+
+	_, _, err = FindByPK[user](ctx, b, "8")
+	assert.NotNil(t, err)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		FindByPK[user](ctx, b, 5)
+	})
+	assert.Contains(t, query, `WHERE ("user"."id" = 5)`)
+}
+
+func TestGetOrCreate(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e = errors.New("an error")
+		u = user{ID: 5, Name: "John"}
+		c = user{ID: 6, Name: "Jane"}
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &u},
+			MockScanOperation{Error: sql.ErrNoRows},
+			MockExecOperation{Returning: &c},
+			MockScanOperation{Error: e},
+			MockScanOperation{Error: sql.ErrNoRows},
+			MockExecOperation{Error: e},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+	args := []any{"John"}
+
+	v, created, err := GetOrCreate(ctx, b, "name = ?", args, user{Name: "John"})
+	assert.Nil(t, err)
+	assert.False(t, created)
+	assert.Equal(t, u, v)
+
+	v, created, err = GetOrCreate(ctx, b, "name = ?", args, user{Name: "Jane"})
+	assert.Nil(t, err)
+	assert.True(t, created)
+	assert.Equal(t, c, v)
+
+	_, created, err = GetOrCreate(ctx, b, "name = ?", args, user{})
+	assert.Equal(t, e, err)
+	assert.False(t, created)
+
+	_, created, err = GetOrCreate(ctx, b, "name = ?", args, user{})
+	assert.Equal(t, e, err)
+	assert.False(t, created)
+	assert.Equal(t, []string{"Scan", "Scan", "Exec", "Scan", "Scan", "Exec"}, ex.CallLog())
+}
+
+func TestExistsPK(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: true, ExpectModel: user{}},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	exists, err := ExistsPK[user](ctx, b, 5)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	_, err = ExistsPK[model](ctx, b, 5)
+	assert.NotNil(t, err)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		ExistsPK[user](ctx, b, 5)
+	})
+	assert.Contains(t, query, `WHERE ("user"."id" = 5)`)
+}
+
+func TestGet(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e = errors.New("an error")
+		u = user{ID: 5, Name: "John"}
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &u},
+			MockScanOperation{Error: sql.ErrNoRows},
+			MockScanOperation{Error: e},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	v := user{ID: 5}
+	found, err := b.Get(ctx, &v)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, u, v)
+
+	found, err = b.Get(ctx, &user{ID: 6})
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	found, err = b.Get(ctx, &user{ID: 7})
+	assert.Equal(t, e, err)
+	assert.False(t, found)
+}
+
+func TestSoftDeleteHelpers(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var p []post
+		b.ScanWhere(ctx, &p, "id > ?", 1)
+	})
+	assert.Contains(t, query, `"post"."deleted_at" IS NULL`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		var p []post
+		b.ScanWhereWithDeleted(ctx, &p, "id > ?", 1)
+	})
+	assert.NotContains(t, query, `IS NULL`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		b.DeleteWherePK(ctx, &post{ID: 1})
+	})
+	assert.Contains(t, query, `UPDATE "posts"`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		b.SoftDelete(ctx, &post{ID: 1})
+	})
+	assert.Contains(t, query, `UPDATE "posts"`)
+	assert.Contains(t, query, `SET "deleted_at" =`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		b.DeleteForced(ctx, &post{ID: 1})
+	})
+	assert.Contains(t, query, `DELETE FROM "posts"`)
+}
+
+func TestTruncate(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	result := MockQueryResult{RowsAffectedValue: 10}
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Result: result},
+			MockExecOperation{Result: result},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	r, err := b.Truncate(ctx, (*model)(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, result, r)
+
+	r, err = b.TruncateCascade(ctx, (*model)(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, result, r)
+
+	// SQLite has no TRUNCATE, so bun deletes every row instead.
+	query := lastSQL(t, func(b Bunoffe) {
+		b.Truncate(ctx, (*model)(nil))
+	})
+	assert.Equal(t, `DELETE FROM "models"`, query)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		b.TruncateCascade(ctx, (*model)(nil))
+	})
+	assert.Equal(t, `DELETE FROM "models"`, query)
+}
+
+func TestScanAggregate(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m []model
+		b.ScanAggregate(ctx, &m, "string", "count(*) > ?", 1)
+	})
+	assert.Contains(t, query, `GROUP BY string HAVING (count(*) > 1)`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		var m []model
+		b.ScanAggregate(ctx, &m, "string", "")
+	})
+	assert.Contains(t, query, `GROUP BY string`)
+	assert.NotContains(t, query, `HAVING`)
+}
+
+func TestQueryRealizerCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	var (
+		r QueryRealizer
+		m model
+	)
+
+	_, err = r.Exec(ctx, db.NewInsert().Model(&m))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = r.Scan(ctx, db.NewSelect().Model(&m))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	exists, err := r.Exists(ctx, db.NewSelect().Model(&m))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, exists)
+}
+
+func TestSelect(t *testing.T) {
+	ctx := context.Background()
+
+	query := lastSQL(t, func(b Bunoffe) {
+		var m []model
+		b.Select(ctx, &m, SelectOptions{})
+	})
+	assert.Equal(
+		t,
+		`SELECT "model"."string", "model"."int" FROM "models" AS "model"`,
+		query,
+	)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		var m []model
+		b.Select(ctx, &m, SelectOptions{
+			Columns:   []string{"string"},
+			Where:     "int > ?",
+			Args:      []any{1},
+			GroupBy:   "string",
+			OrderBy:   "string DESC",
+			Limit:     10,
+			Offset:    20,
+			ForUpdate: true,
+		})
+	})
+	assert.Equal(
+		t,
+		`SELECT "model"."string" FROM "models" AS "model" `+
+			`WHERE (int > 1) GROUP BY string ORDER BY string DESC `+
+			`LIMIT 10 OFFSET 20 FOR UPDATE`,
+		query,
+	)
+}
+
+func TestExistsByPK(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: true},
+		},
+	}
+	b := Bunoffe{X: ex, DB: db}
+
+	exists, err := b.ExistsByPK(ctx, &user{ID: 5})
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		b.ExistsByPK(ctx, &user{ID: 5})
+	})
+	assert.Contains(t, query, `WHERE ("user"."id" = 5)`)
+}
+
+func TestQueryRealizerRaw(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM models WHERE int > 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec(`DELETE FROM models`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	var (
+		r QueryRealizer
+		c int
+	)
+
+	err = r.ScanRaw(ctx, db.NewRaw("SELECT count(*) FROM models WHERE int > ?", 1), &c)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, c)
+
+	res, err := r.ExecRaw(ctx, db.NewRaw("DELETE FROM models"))
+	assert.Nil(t, err)
+	n, _ := res.RowsAffected()
+	assert.Equal(t, int64(3), n)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryRealizerHooks(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	e := errors.New("an error")
+
+	mock.ExpectExec(`DELETE FROM "models"`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(`SELECT`).WillReturnError(e)
+
+	// results
+	var (
+		queries []any
+		results []sql.Result
+		errs    []error
+	)
+
+	r := NewQueryRealizer(
+		WithBeforeHook(func(ctx context.Context, q any) {
+			queries = append(queries, q)
+		}),
+		WithAfterHook(func(ctx context.Context, res sql.Result, err error) {
+			results = append(results, res)
+			errs = append(errs, err)
+		}),
+	)
+
+	var m model
+	del := db.NewDelete().Model(&m).Where("1 = 1")
+	res, err := r.Exec(ctx, del)
+	assert.Nil(t, err)
+
+	sel := db.NewSelect().Model(&m)
+	err = r.Scan(ctx, sel)
+	assert.Equal(t, e, err)
+
+	assert.Equal(t, []any{del, sel}, queries)
+	assert.Equal(t, []sql.Result{res, nil}, results)
+	assert.Equal(t, []error{nil, e}, errs)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryRealizerHooksOnEveryMethod(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// ScanAndCount runs its queries concurrently.
+	mock.MatchExpectationsInOrder(false)
+
+	// expected
+	mock.ExpectExec(`^DELETE FROM "models"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`^SELECT "model"`).
+		WillReturnRows(sqlmock.NewRows([]string{"string", "int"}).AddRow("a", 1))
+	mock.ExpectQuery(`^SELECT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`^SELECT count\(\*\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`^SELECT "model"`).
+		WillReturnRows(sqlmock.NewRows([]string{"string", "int"}).AddRow("a", 1))
+	mock.ExpectQuery(`^SELECT count\(\*\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`^SELECT 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	mock.ExpectExec(`^UPDATE models`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// results
+	var (
+		before  int
+		results []sql.Result
+	)
+
+	r := NewQueryRealizer(
+		WithBeforeHook(func(ctx context.Context, q any) {
+			before++
+		}),
+		WithAfterHook(func(ctx context.Context, res sql.Result, err error) {
+			assert.Nil(t, err)
+			results = append(results, res)
+		}),
+	)
+
+	var (
+		m  model
+		ms []model
+		n  int
+	)
+
+	res, err := r.Exec(ctx, db.NewDelete().Model(&m).Where("1 = 1"))
+	assert.Nil(t, err)
+
+	err = r.Scan(ctx, db.NewSelect().Model(&m))
+	assert.Nil(t, err)
+
+	_, err = r.Exists(ctx, db.NewSelect().Model(&m))
+	assert.Nil(t, err)
+
+	_, err = r.Count(ctx, db.NewSelect().Model(&m))
+	assert.Nil(t, err)
+
+	_, err = r.ScanAndCount(ctx, db.NewSelect().Model(&ms))
+	assert.Nil(t, err)
+
+	err = r.ScanRaw(ctx, db.NewRaw("SELECT 1"), &n)
+	assert.Nil(t, err)
+
+	raw, err := r.ExecRaw(ctx, db.NewRaw("UPDATE models SET int = 0"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 7, before)
+	assert.Equal(t, []sql.Result{res, nil, nil, nil, nil, nil, raw}, results)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,100 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// InstrumentedExecutor is an Executor that delegates the queries to
+// Inner and reports each call to Observe, e.g. to collect metrics. The
+// values and errors returned by Inner are returned unchanged.
+type InstrumentedExecutor struct {
+	Inner Executor
+
+	// Observe receives the name of the Executor method that was called,
+	// how long the call took, and the error it returned, if any. If
+	// Observe is nil, nothing is reported.
+	Observe func(op string, dur time.Duration, err error)
+
+	// Now is the clock the durations are measured with. If it's nil,
+	// time.Now is used. Tests can set it to get exact durations.
+	Now func() time.Time
+}
+
+// Exec delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) Exec(
+	ctx context.Context,
+	q ExecQuery,
+	args ...any,
+) (sql.Result, error) {
+	start := x.now()
+	res, err := x.Inner.Exec(ctx, q, args...)
+	x.observe("Exec", start, err)
+	return res, err
+}
+
+// Scan delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	start := x.now()
+	err := x.Inner.Scan(ctx, q, args...)
+	x.observe("Scan", start, err)
+	return err
+}
+
+// Exists delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	start := x.now()
+	exists, err := x.Inner.Exists(ctx, q)
+	x.observe("Exists", start, err)
+	return exists, err
+}
+
+// Count delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) Count(ctx context.Context, q CountQuery) (int, error) {
+	start := x.now()
+	count, err := x.Inner.Count(ctx, q)
+	x.observe("Count", start, err)
+	return count, err
+}
+
+// ScanAndCount delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	start := x.now()
+	count, err := x.Inner.ScanAndCount(ctx, q, args...)
+	x.observe("ScanAndCount", start, err)
+	return count, err
+}
+
+// ScanRaw delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	start := x.now()
+	err := x.Inner.ScanRaw(ctx, q, dest...)
+	x.observe("ScanRaw", start, err)
+	return err
+}
+
+// ExecRaw delegates the query to Inner and reports the call.
+func (x InstrumentedExecutor) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	start := x.now()
+	res, err := x.Inner.ExecRaw(ctx, q)
+	x.observe("ExecRaw", start, err)
+	return res, err
+}
+
+func (x InstrumentedExecutor) observe(op string, start time.Time, err error) {
+	if x.Observe != nil {
+		x.Observe(op, x.now().Sub(start), err)
+	}
+}
+
+func (x InstrumentedExecutor) now() time.Time {
+	if x.Now != nil {
+		return x.Now()
+	}
+	return time.Now()
+}
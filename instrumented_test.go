@@ -0,0 +1,76 @@
+package bunoffe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedExecutor(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		e      = errors.New("an error")
+		m      = model{String: "Hello, world!", Int: 33}
+		result = NewResult(1, 1)
+	)
+
+	x := InstrumentedExecutor{
+		Inner: &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExecOperation{Result: result},
+				MockScanOperation{Model: &m},
+				MockExistsOperation{Error: e},
+			},
+		},
+	}
+
+	// results
+	var (
+		ops  []string
+		durs []time.Duration
+		errs []error
+	)
+
+	x.Now = fakeClock(time.Second)
+	x.Observe = func(op string, dur time.Duration, err error) {
+		ops = append(ops, op)
+		durs = append(durs, dur)
+		errs = append(errs, err)
+	}
+
+	var n model
+
+	res, err := x.Exec(ctx, db.NewInsert().Model(&n))
+	assert.Nil(t, err)
+	assert.Equal(t, result, res)
+
+	err = x.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	exists, err := x.Exists(ctx, db.NewSelect().Model(&n))
+	assert.Equal(t, e, err)
+	assert.False(t, exists)
+
+	assert.Equal(t, []string{"Exec", "Scan", "Exists"}, ops)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, durs)
+	assert.Equal(t, []error{nil, nil, e}, errs)
+}
+
+// fakeClock returns a clock that advances by step each time it's read.
+func fakeClock(step time.Duration) func() time.Time {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
@@ -0,0 +1,143 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+// LoggingRealizer is an Executor that behaves like QueryRealizer, but
+// reports every executed query to Log and Logf, including the ones that
+// fail. Setting Threshold restricts it to slow queries, surfacing N+1
+// queries or missing indexes.
+type LoggingRealizer struct {
+	// Log receives the query's SQL, how long it took to run, and the
+	// error it returned, if any. If Log is nil, nothing is logged to it.
+	Log func(query string, dur time.Duration, err error)
+
+	// Logf, if not nil, receives a formatted message with the same
+	// information as Log, so it can be, e.g., log.Printf or t.Logf.
+	Logf func(format string, args ...any)
+
+	// Threshold is how long a query must take to be logged. If it's
+	// zero, every query is logged.
+	Threshold time.Duration
+
+	// Now is the clock the durations are measured with. If it's nil,
+	// time.Now is used. Tests can set it to get exact durations.
+	Now func() time.Time
+
+	// Realizer runs the queries, so a realizer created with hooks keeps
+	// them when logged.
+	Realizer QueryRealizer
+}
+
+// Exec executes the query like QueryRealizer.Exec and logs it.
+func (r LoggingRealizer) Exec(
+	ctx context.Context,
+	q ExecQuery,
+	args ...any,
+) (sql.Result, error) {
+	start := r.now()
+	res, err := r.Realizer.Exec(ctx, q, args...)
+	r.log(q, start, err)
+	return res, err
+}
+
+// Scan executes the query like QueryRealizer.Scan and logs it.
+func (r LoggingRealizer) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	start := r.now()
+	err := r.Realizer.Scan(ctx, q, args...)
+	r.log(q, start, err)
+	return err
+}
+
+// Exists executes the query like QueryRealizer.Exists and logs it.
+func (r LoggingRealizer) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	start := r.now()
+	exists, err := r.Realizer.Exists(ctx, q)
+	r.log(q, start, err)
+	return exists, err
+}
+
+// Count executes the query like QueryRealizer.Count and logs it.
+func (r LoggingRealizer) Count(ctx context.Context, q CountQuery) (int, error) {
+	start := r.now()
+	count, err := r.Realizer.Count(ctx, q)
+	r.log(q, start, err)
+	return count, err
+}
+
+// ScanAndCount executes the query like QueryRealizer.ScanAndCount and
+// logs it.
+func (r LoggingRealizer) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	start := r.now()
+	count, err := r.Realizer.ScanAndCount(ctx, q, args...)
+	r.log(q, start, err)
+	return count, err
+}
+
+// ScanRaw executes the query like QueryRealizer.ScanRaw and logs it.
+func (r LoggingRealizer) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	start := r.now()
+	err := r.Realizer.ScanRaw(ctx, q, dest...)
+	r.log(q, start, err)
+	return err
+}
+
+// ExecRaw executes the query like QueryRealizer.ExecRaw and logs it.
+func (r LoggingRealizer) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	start := r.now()
+	res, err := r.Realizer.ExecRaw(ctx, q)
+	r.log(q, start, err)
+	return res, err
+}
+
+func (r LoggingRealizer) log(q any, start time.Time, err error) {
+	dur := r.now().Sub(start)
+	if dur < r.Threshold || (r.Log == nil && r.Logf == nil) {
+		return
+	}
+
+	query := querySQL(q)
+	if r.Log != nil {
+		r.Log(query, dur, err)
+	}
+	if r.Logf != nil && err != nil {
+		r.Logf("query took %v and failed with %v: %s", dur, err, query)
+	} else if r.Logf != nil {
+		r.Logf("query took %v: %s", dur, query)
+	}
+}
+
+func (r LoggingRealizer) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// querySQL renders the SQL of a bun query. It returns an empty string
+// if q isn't a bun query or can't be rendered.
+func querySQL(q any) string {
+	bq, ok := q.(interface {
+		schema.QueryAppender
+		DB() *bun.DB
+	})
+	if !ok || bq.DB() == nil {
+		return ""
+	}
+
+	b, err := bq.AppendQuery(bq.DB().Formatter(), nil)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,85 @@
+package bunoffe
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingRealizer(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// results
+	var (
+		query  string
+		logErr error
+		calls  int
+	)
+
+	r := LoggingRealizer{
+		Log: func(q string, _ time.Duration, err error) {
+			query = q
+			logErr = err
+			calls++
+		},
+	}
+
+	// The mocked database has no expectations, so every query fails.
+	var m model
+	exists, err := r.Exists(ctx, db.NewSelect().Model(&m).Where("int = ?", 33))
+	assert.False(t, exists)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, err, logErr)
+	assert.Contains(t, query, `WHERE (int = 33)`)
+
+	_, err = r.Exec(ctx, db.NewInsert().Model(&m))
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Contains(t, query, `INSERT INTO "models"`)
+
+	// The hooks of the realizer run too.
+	var hooked int
+	r.Realizer = NewQueryRealizer(
+		WithBeforeHook(func(ctx context.Context, q any) { hooked++ }),
+	)
+	_, err = r.Count(ctx, db.NewSelect().Model(&m))
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 1, hooked)
+}
+
+func TestLoggingRealizerThreshold(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// results
+	var messages []string
+
+	r := LoggingRealizer{
+		Logf: func(format string, args ...any) {
+			messages = append(messages, fmt.Sprintf(format, args...))
+		},
+		Threshold: 2 * time.Second,
+		Now:       fakeClock(time.Second),
+	}
+
+	var m model
+	r.Exists(ctx, db.NewSelect().Model(&m))
+	assert.Empty(t, messages)
+
+	r.Now = fakeClock(2 * time.Second)
+	r.Exists(ctx, db.NewSelect().Model(&m).Where("int = ?", 33))
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "query took 2s and failed with")
+	assert.Contains(t, messages[0], `WHERE (int = 33)`)
+}
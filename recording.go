@@ -0,0 +1,79 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+)
+
+type (
+	// RecordingExecutor is an Executor that delegates the queries to
+	// Inner and records each call in Calls.
+	RecordingExecutor struct {
+		Inner Executor
+		Calls []Call
+	}
+
+	// Call is a call recorded by the RecordingExecutor.
+	Call struct {
+		// Method is the name of the Executor method that was called.
+		Method string
+
+		// SQL is the compiled SQL of the query. It's empty if the query
+		// couldn't be compiled.
+		SQL string
+	}
+)
+
+// Exec records the call and delegates it to Inner.
+func (r *RecordingExecutor) Exec(
+	ctx context.Context,
+	q ExecQuery,
+	args ...any,
+) (sql.Result, error) {
+	r.record("Exec", q)
+	return r.Inner.Exec(ctx, q, args...)
+}
+
+// Scan records the call and delegates it to Inner.
+func (r *RecordingExecutor) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	r.record("Scan", q)
+	return r.Inner.Scan(ctx, q, args...)
+}
+
+// Exists records the call and delegates it to Inner.
+func (r *RecordingExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	r.record("Exists", q)
+	return r.Inner.Exists(ctx, q)
+}
+
+// Count records the call and delegates it to Inner.
+func (r *RecordingExecutor) Count(ctx context.Context, q CountQuery) (int, error) {
+	r.record("Count", q)
+	return r.Inner.Count(ctx, q)
+}
+
+// ScanAndCount records the call and delegates it to Inner.
+func (r *RecordingExecutor) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	r.record("ScanAndCount", q)
+	return r.Inner.ScanAndCount(ctx, q, args...)
+}
+
+// ScanRaw records the call and delegates it to Inner.
+func (r *RecordingExecutor) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	r.record("ScanRaw", q)
+	return r.Inner.ScanRaw(ctx, q, dest...)
+}
+
+// ExecRaw records the call and delegates it to Inner.
+func (r *RecordingExecutor) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	r.record("ExecRaw", q)
+	return r.Inner.ExecRaw(ctx, q)
+}
+
+func (r *RecordingExecutor) record(method string, q any) {
+	r.Calls = append(r.Calls, Call{Method: method, SQL: querySQL(q)})
+}
@@ -0,0 +1,44 @@
+package bunoffe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingExecutor(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := model{String: "Hello, world!", Int: 33}
+
+	r := &RecordingExecutor{
+		Inner: &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{Exists: true},
+				MockScanOperation{Model: &m},
+			},
+		},
+	}
+
+	// results
+	var n model
+
+	exists, err := r.Exists(ctx, db.NewSelect().Model(&n).Where("int = ?", 33))
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	err = r.Scan(ctx, db.NewSelect().Model(&n).Where("int = ?", 33))
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	require.Len(t, r.Calls, 2)
+	assert.Equal(t, "Exists", r.Calls[0].Method)
+	assert.Equal(t, "Scan", r.Calls[1].Method)
+	assert.Contains(t, r.Calls[1].SQL, `WHERE (int = 33)`)
+}
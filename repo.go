@@ -0,0 +1,47 @@
+package bunoffe
+
+import "context"
+
+// Repo is a Bunoffe whose models are Ts, so its methods allocate the
+// models instead of the caller. Its queries go through the Executor of
+// the Bunoffe, so a Repo is mockable like a Bunoffe. For instance:
+//
+//	users := bunoffe.NewRepo[User](b)
+//	u, err := users.GetByPK(ctx, 5)
+type Repo[T any] struct {
+	Bunoffe
+}
+
+// NewRepo creates a Repo of Ts over b.
+func NewRepo[T any](b Bunoffe) Repo[T] {
+	return Repo[T]{Bunoffe: b}
+}
+
+// GetByPK scans the row whose primary keys are keys into a new T. The
+// keys are values, one per primary key column in the order the columns
+// are declared in T, not column names like the pks of ScanWherePK. If
+// there's no such row, it returns sql.ErrNoRows.
+func (r Repo[T]) GetByPK(ctx context.Context, keys ...any) (*T, error) {
+	m, err := newWithPK[T](r.DB, keys...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ScanWherePK(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// List scans the rows that satisfy the condition into a slice of Ts.
+// If cond is empty, it lists every row.
+func (r Repo[T]) List(ctx context.Context, cond string, condArgs ...any) ([]T, error) {
+	var ms []T
+	q := r.DB.NewSelect().Model(&ms)
+	if cond != "" {
+		q = q.Where(cond, condArgs...)
+	}
+	if err := r.X.Scan(ctx, q); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
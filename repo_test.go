@@ -0,0 +1,62 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		u  = user{ID: 5, Name: "John"}
+		us = []user{{ID: 5, Name: "John"}, {ID: 6, Name: "Jane"}}
+	)
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &u, ExpectPK: 5},
+			MockScanOperation{Error: sql.ErrNoRows},
+			MockScanOperation{Model: &us},
+			MockScanOperation{Model: &us},
+		},
+	}
+	r := NewRepo[user](Bunoffe{X: ex, DB: db})
+
+	v, err := r.GetByPK(ctx, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, &u, v)
+
+	v, err = r.GetByPK(ctx, 6)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Nil(t, v)
+
+	_, err = r.GetByPK(ctx, 5, 6)
+	assert.EqualError(t, err, "bunoffe: bunoffe.user has 1 primary keys, but 2 were given")
+
+	vs, err := r.List(ctx, "name LIKE ?", "J%")
+	assert.Nil(t, err)
+	assert.Equal(t, us, vs)
+
+	vs, err = r.List(ctx, "")
+	assert.Nil(t, err)
+	assert.Equal(t, us, vs)
+
+	query := lastSQL(t, func(b Bunoffe) {
+		NewRepo[user](b).List(ctx, "name LIKE ?", "J%")
+	})
+	assert.Contains(t, query, `FROM "users" AS "user" WHERE (name LIKE 'J%')`)
+
+	query = lastSQL(t, func(b Bunoffe) {
+		NewRepo[user](b).List(ctx, "")
+	})
+	assert.NotContains(t, query, "WHERE")
+}
@@ -0,0 +1,147 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// RetryingExecutor is an Executor that delegates the queries to Inner
+// and runs them again when they fail with a transient error, such as a
+// deadlock or a serialization failure.
+//
+// Retries stop as soon as the context is done: the context's error is
+// returned instead of the query's, and the backoff between attempts is
+// cut short.
+type RetryingExecutor struct {
+	Inner Executor
+
+	// MaxAttempts is the maximum number of times a query runs, counting
+	// the first one. Values lower than 1 mean a single attempt.
+	MaxAttempts int
+
+	// ShouldRetry reports whether a query that failed with err should
+	// run again. If ShouldRetry is nil, no error is retried. Either way,
+	// sql.ErrNoRows and context errors are never retried, since running
+	// the query again wouldn't change them.
+	ShouldRetry func(err error) bool
+
+	// If Backoff is not nil, the executor waits Backoff(attempt) before
+	// each retry, where attempt is the number of failed attempts so far.
+	Backoff func(attempt int) time.Duration
+}
+
+// Exec delegates the query to Inner, retrying it on transient errors.
+func (r RetryingExecutor) Exec(
+	ctx context.Context,
+	q ExecQuery,
+	args ...any,
+) (sql.Result, error) {
+	return retry(ctx, r, func() (sql.Result, error) {
+		return r.Inner.Exec(ctx, q, args...)
+	})
+}
+
+// Scan delegates the query to Inner, retrying it on transient errors.
+func (r RetryingExecutor) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	_, err := retry(ctx, r, func() (struct{}, error) {
+		return struct{}{}, r.Inner.Scan(ctx, q, args...)
+	})
+	return err
+}
+
+// Exists delegates the query to Inner, retrying it on transient errors.
+// Like with the other methods, only errors are retried: a row that
+// doesn't exist isn't a failure.
+func (r RetryingExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	return retry(ctx, r, func() (bool, error) {
+		return r.Inner.Exists(ctx, q)
+	})
+}
+
+// Count delegates the query to Inner, retrying it on transient errors.
+func (r RetryingExecutor) Count(ctx context.Context, q CountQuery) (int, error) {
+	return retry(ctx, r, func() (int, error) {
+		return r.Inner.Count(ctx, q)
+	})
+}
+
+// ScanAndCount delegates the query to Inner, retrying it on transient
+// errors.
+func (r RetryingExecutor) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	return retry(ctx, r, func() (int, error) {
+		return r.Inner.ScanAndCount(ctx, q, args...)
+	})
+}
+
+// ScanRaw delegates the query to Inner, retrying it on transient errors.
+func (r RetryingExecutor) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	_, err := retry(ctx, r, func() (struct{}, error) {
+		return struct{}{}, r.Inner.ScanRaw(ctx, q, dest...)
+	})
+	return err
+}
+
+// ExecRaw delegates the query to Inner, retrying it on transient errors.
+func (r RetryingExecutor) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	return retry(ctx, r, func() (sql.Result, error) {
+		return r.Inner.ExecRaw(ctx, q)
+	})
+}
+
+// retry calls f until it succeeds, fails with an error that shouldn't
+// be retried, runs r.MaxAttempts times, or ctx is done.
+func retry[T any](
+	ctx context.Context,
+	r RetryingExecutor,
+	f func() (T, error),
+) (T, error) {
+	for attempt := 1; ; attempt++ {
+		v, err := f()
+		if err == nil || attempt >= r.MaxAttempts {
+			return v, err
+		}
+		if !r.retryable(err) {
+			return v, err
+		}
+		if err := r.wait(ctx, attempt); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// retryable reports whether a query that failed with err should run
+// again.
+func (r RetryingExecutor) retryable(err error) bool {
+	switch {
+	case errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return r.ShouldRetry != nil && r.ShouldRetry(err)
+}
+
+// wait blocks for the backoff of the attempt, returning early with the
+// context's error if ctx is done.
+func (r RetryingExecutor) wait(ctx context.Context, attempt int) error {
+	if r.Backoff == nil {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(r.Backoff(attempt))
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryingExecutor(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		deadlock = errors.New("deadlock")
+		fatal    = errors.New("fatal")
+		result   = NewResult(0, 1)
+	)
+
+	transient := func(err error) bool { return errors.Is(err, deadlock) }
+
+	t.Run("test retries until success", func(t *testing.T) {
+		var backoffs []int
+
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExecOperation{Error: deadlock},
+				MockExecOperation{Error: deadlock},
+				MockExecOperation{Result: result},
+			},
+		}
+		r := RetryingExecutor{
+			Inner:       ex,
+			MaxAttempts: 3,
+			ShouldRetry: transient,
+			Backoff: func(attempt int) time.Duration {
+				backoffs = append(backoffs, attempt)
+				return time.Millisecond
+			},
+		}
+
+		var m model
+		res, err := r.Exec(ctx, db.NewInsert().Model(&m))
+		assert.Nil(t, err)
+		assert.Equal(t, result, res)
+		assert.Equal(t, []int{1, 2}, backoffs)
+		assert.Len(t, ex.CallLog(), 3)
+	})
+
+	t.Run("test stops at max attempts", func(t *testing.T) {
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockScanOperation{Error: deadlock},
+				MockScanOperation{Error: deadlock},
+			},
+		}
+		r := RetryingExecutor{Inner: ex, MaxAttempts: 2, ShouldRetry: transient}
+
+		var m model
+		err := r.Scan(ctx, db.NewSelect().Model(&m))
+		assert.Equal(t, deadlock, err)
+		assert.Len(t, ex.CallLog(), 2)
+	})
+
+	t.Run("test doesn't retry other errors", func(t *testing.T) {
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{Error: fatal},
+			},
+		}
+		r := RetryingExecutor{Inner: ex, MaxAttempts: 3, ShouldRetry: transient}
+
+		var m model
+		_, err := r.Exists(ctx, db.NewSelect().Model(&m))
+		assert.Equal(t, fatal, err)
+		assert.Len(t, ex.CallLog(), 1)
+	})
+
+	t.Run("test doesn't retry by default", func(t *testing.T) {
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockScanOperation{Error: deadlock},
+				MockScanOperation{Error: deadlock},
+			},
+		}
+		r := RetryingExecutor{Inner: ex, MaxAttempts: 3}
+
+		var m model
+		err := r.Scan(ctx, db.NewSelect().Model(&m))
+		assert.Equal(t, deadlock, err)
+		assert.Len(t, ex.CallLog(), 1)
+	})
+
+	t.Run("test never retries missing rows or context errors", func(t *testing.T) {
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockScanOperation{Error: sql.ErrNoRows},
+				MockScanOperation{Error: context.Canceled},
+				MockScanOperation{Error: context.DeadlineExceeded},
+			},
+		}
+		r := RetryingExecutor{
+			Inner:       ex,
+			MaxAttempts: 3,
+			ShouldRetry: func(error) bool { return true },
+		}
+		b := Bunoffe{X: r, DB: db}
+
+		found, err := b.Get(ctx, &user{ID: 5})
+		assert.Nil(t, err)
+		assert.False(t, found)
+
+		var m model
+		err = r.Scan(ctx, db.NewSelect().Model(&m))
+		assert.Equal(t, context.Canceled, err)
+
+		err = r.Scan(ctx, db.NewSelect().Model(&m))
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Len(t, ex.CallLog(), 3)
+	})
+
+	t.Run("test retries exists", func(t *testing.T) {
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{Error: deadlock},
+				MockExistsOperation{Exists: false},
+			},
+		}
+		r := RetryingExecutor{Inner: ex, MaxAttempts: 3, ShouldRetry: transient}
+
+		var m model
+		exists, err := r.Exists(ctx, db.NewSelect().Model(&m))
+		assert.Nil(t, err)
+		assert.False(t, exists)
+		assert.Len(t, ex.CallLog(), 2)
+	})
+
+	t.Run("test stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExecOperation{Error: deadlock},
+				MockExecOperation{Result: result},
+			},
+		}
+		r := RetryingExecutor{
+			Inner:       ex,
+			MaxAttempts: 2,
+			ShouldRetry: transient,
+			Backoff:     func(int) time.Duration { return time.Hour },
+		}
+
+		var m model
+		_, err := r.Exec(ctx, db.NewInsert().Model(&m))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, ex.CallLog(), 1)
+	})
+}
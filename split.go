@@ -0,0 +1,69 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SplitExecutor is an Executor that routes read queries to Reader and
+// write queries to Writer, e.g. QueryRealizers whose queries are built
+// against a read replica and the primary database, respectively.
+//
+// Scan, Exists, Count, and ScanAndCount are reads; Exec is a write. Raw
+// queries go to Writer, unless RawReads is true, in which case ScanRaw
+// goes to Reader. Note that a write scanned with Scan, such as an
+// INSERT with a RETURNING clause, goes to Reader too.
+type SplitExecutor struct {
+	Reader Executor
+	Writer Executor
+
+	// If RawReads is true, ScanRaw is routed to Reader.
+	RawReads bool
+}
+
+// Exec delegates the query to Writer.
+func (x SplitExecutor) Exec(
+	ctx context.Context,
+	q ExecQuery,
+	args ...any,
+) (sql.Result, error) {
+	return x.Writer.Exec(ctx, q, args...)
+}
+
+// Scan delegates the query to Reader.
+func (x SplitExecutor) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	return x.Reader.Scan(ctx, q, args...)
+}
+
+// Exists delegates the query to Reader.
+func (x SplitExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	return x.Reader.Exists(ctx, q)
+}
+
+// Count delegates the query to Reader.
+func (x SplitExecutor) Count(ctx context.Context, q CountQuery) (int, error) {
+	return x.Reader.Count(ctx, q)
+}
+
+// ScanAndCount delegates the query to Reader.
+func (x SplitExecutor) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	return x.Reader.ScanAndCount(ctx, q, args...)
+}
+
+// ScanRaw delegates the query to Writer, or to Reader if RawReads is
+// true.
+func (x SplitExecutor) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	if x.RawReads {
+		return x.Reader.ScanRaw(ctx, q, dest...)
+	}
+	return x.Writer.ScanRaw(ctx, q, dest...)
+}
+
+// ExecRaw delegates the query to Writer.
+func (x SplitExecutor) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	return x.Writer.ExecRaw(ctx, q)
+}
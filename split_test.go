@@ -0,0 +1,54 @@
+package bunoffe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitExecutor(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	reader := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{},
+			MockExistsOperation{},
+			MockCountOperation{},
+			MockScanAndCountOperation{},
+			MockRawScanOperation{},
+		},
+	}
+	writer := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{},
+			MockRawScanOperation{},
+			MockRawExecOperation{},
+		},
+	}
+
+	x := SplitExecutor{Reader: reader, Writer: writer}
+
+	var m model
+	x.Scan(ctx, db.NewSelect().Model(&m))
+	x.Exists(ctx, db.NewSelect().Model(&m))
+	x.Count(ctx, db.NewSelect().Model(&m))
+	x.ScanAndCount(ctx, db.NewSelect().Model(&m))
+	x.Exec(ctx, db.NewInsert().Model(&m))
+	x.ScanRaw(ctx, db.NewRaw("SELECT 1"))
+	x.ExecRaw(ctx, db.NewRaw("DELETE FROM models"))
+
+	x.RawReads = true
+	x.ScanRaw(ctx, db.NewRaw("SELECT 1"))
+
+	assert.Equal(
+		t,
+		[]string{"Scan", "Exists", "Count", "ScanAndCount", "ScanRaw"},
+		reader.CallLog(),
+	)
+	assert.Equal(t, []string{"Exec", "ScanRaw", "ExecRaw"}, writer.CallLog())
+}
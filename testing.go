@@ -3,12 +3,30 @@ package bunoffe
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/schema"
+)
+
+var (
+	// ErrOpsExhausted is wrapped by the value MockQueryExecutor panics
+	// with when a call finds no operation left to serve it.
+	ErrOpsExhausted = errors.New("bunoffe: mock operations exhausted")
+
+	// ErrOpMismatch is wrapped by the value MockQueryExecutor panics
+	// with when the next operation doesn't match the called method, or,
+	// if Unordered is true, when only operations of other types are
+	// pending.
+	ErrOpMismatch = errors.New("bunoffe: mock operation mismatch")
 )
 
 type (
@@ -21,23 +39,72 @@ type (
 		// is called, next operation in line (starting with the first)
 		// will be executed.
 		Ops []MockedQueryOperation
-		idx int
+
+		// If FailWithT is not nil, a mismatched or exhausted operation
+		// fails the test with FailWithT.Fatalf instead of panicking. The
+		// panic value is an error; see ErrOpsExhausted and ErrOpMismatch.
+		FailWithT testing.TB
+
+		// If CommitError is not nil, RunInTx returns it when the
+		// transaction would be committed, simulating a failed commit.
+		CommitError error
+
+		// If IgnoreContext is true, the executor serves its operations
+		// even if the context is done. Otherwise, it returns the
+		// context's error without consuming an operation.
+		IgnoreContext bool
+
+		// ExpectedCalls is the number of Executor method calls
+		// AssertCallCount expects to have been served, or to have found
+		// no operation left.
+		ExpectedCalls int
+
+		// ExecCalls, ScanCalls, and ExistsCalls count the calls to Exec,
+		// Scan, and Exists, respectively. See CallLog for every call.
+		ExecCalls   int
+		ScanCalls   int
+		ExistsCalls int
+
+		// If Unordered is true, each Executor method call is served
+		// by the first pending operation of the matching type, instead
+		// of by the next operation in line.
+		Unordered bool
+
+		idx      int
+		unserved int
+		cur      int
+		consumed []bool
+		calls    []string
+		models   []any
 	}
 
 	// MockedQueryOperation is interface that works as common type
 	// for all mock operations.
 	MockedQueryOperation interface {
 		doNothing()
+		name() string
 	}
 
 	// MockExecOperation is a type to mock a Exec call.
 	MockExecOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
 		// If Model is not nil and Error is nil, when Exec is called, it will
 		// contain the value passed to the query method `.Model(&m)`.
 		Model any
 
+		// If Returning is not nil and Error is nil, when Exec is called, it
+		// will be assigned to the value passed to the query method
+		// `.Model(&m)`, like the columns of a RETURNING clause.
+		Returning any
+
 		// If Args is not nil and Error is nil, when Exec is called, each of
-		// its values will be assigned to parameter `...args`.
+		// its values will be assigned to parameter `...args`, by position:
+		// Args[i] to args[i]. A nil value leaves its destination untouched.
+		// Query parameters, positional or named (e.g. `?id`), are part of
+		// the query, not of `...args`, so they're neither matched nor
+		// counted.
 		Args []any
 
 		// If Result is not nil and Error is nil, when Exec is called, it will
@@ -47,29 +114,168 @@ type (
 		// If Error is not nil, Exec will return a nil sql.Result and this
 		// Error.
 		Error error
+
+		// If Do is not nil, Exec calls it and returns its results. The
+		// other fields are ignored.
+		Do func(q ExecQuery, args []any) (sql.Result, error)
+
+		// If ExpectSQL is not nil, Exec compiles the query and fails if
+		// its SQL doesn't match ExpectSQL.
+		ExpectSQL *regexp.Regexp
+
+		// If ExpectArgs is not nil, Exec fails if the parameter `...args`
+		// isn't deeply equal to it. Unlike Args, which are assigned to
+		// the args, ExpectArgs only asserts them.
+		ExpectArgs []any
+
+		// If ExpectPK is not nil, Exec fails if the primary key of the
+		// value passed to the query method `.Model(&m)` differs from it,
+		// e.g. when it wasn't set before an update or delete.
+		ExpectPK any
+
+		// Delay is how long Exec waits before serving the operation. If
+		// the context is done meanwhile, Exec returns its error.
+		Delay time.Duration
 	}
 
 	// MockScanOperation is a type to mock a Scan call.
 	MockScanOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
 		// If Model is not nil and Error is nil, when Scan is called, it will
 		// be assigned the value passed to the query method `.Model(&m)`.
 		Model any
 
-		// If Args is not nil and Error is nil, when Exec is called, each of
-		// its values will be assigned to parameter `...args`.
+		// If Args is not nil and Error is nil, when Scan is called, each of
+		// its values will be assigned to parameter `...args`, i.e. the
+		// destinations other than the model, by position: Args[i] to
+		// args[i]. A nil value leaves its destination untouched. Args must
+		// have one value per destination.
 		Args []any
 
 		// If Error is not nil, Scan will return it.
 		Error error
+
+		// If Produce is not nil and Error is nil, Scan calls it with the
+		// query and assigns its result to the query model, instead of
+		// Model. It allows the scanned row to depend on the query.
+		Produce func(q ScanQuery) any
+
+		// If ExpectPK is not nil, Scan fails if the primary key of the
+		// value passed to the query method `.Model(&m)` differs from it.
+		ExpectPK any
+
+		// Delay is how long Scan waits before serving the operation. If
+		// the context is done meanwhile, Scan returns its error.
+		Delay time.Duration
 	}
 
 	MockExistsOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
 		// If Error is not nil, this value will be returned when Exists is
 		// called. Otherwise false is returned.
 		Exists bool
 
 		// If Error is not nil, Scan will return it.
 		Error error
+
+		// If ExpectModel is not nil, Exists checks that the value passed
+		// to the query method `.Model(&m)` has the same type as
+		// ExpectModel, ignoring pointers, and fails otherwise.
+		ExpectModel any
+
+		// Delay is how long Exists waits before serving the operation.
+		// If the context is done meanwhile, Exists returns its error.
+		Delay time.Duration
+	}
+
+	// MockCountOperation is a type to mock a Count call.
+	MockCountOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
+		// If Error is nil, this value will be returned when Count is
+		// called.
+		Count int
+
+		// If Error is not nil, Count will return it.
+		Error error
+	}
+
+	// MockScanAndCountOperation is a type to mock a ScanAndCount call.
+	MockScanAndCountOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
+		// If Model is not nil and Error is nil, when ScanAndCount is
+		// called, it will be assigned the value passed to the query
+		// method `.Model(&m)`.
+		Model any
+
+		// If Args is not nil and Error is nil, when ScanAndCount is
+		// called, each of its values will be assigned to parameter
+		// `...args` by position, like MockScanOperation.Args. Args must
+		// have one value per destination.
+		Args []any
+
+		// If Error is nil, this value will be returned when ScanAndCount
+		// is called.
+		Count int
+
+		// If Error is not nil, ScanAndCount will return it.
+		Error error
+	}
+
+	// MockRawScanOperation is a type to mock a raw query Scan call.
+	MockRawScanOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
+		// If Dest is not nil and Error is nil, when ScanRaw is called,
+		// each of its values will be assigned to parameter `...dest`, by
+		// position. A nil value leaves its destination untouched.
+		Dest []any
+
+		// If Error is not nil, ScanRaw will return it.
+		Error error
+	}
+
+	// MockRawExecOperation is a type to mock a raw query Exec call.
+	MockRawExecOperation struct {
+		// Name optionally identifies the operation in failure messages.
+		Name string
+
+		// If Result is not nil and Error is nil, when ExecRaw is called,
+		// it will return Result.
+		Result sql.Result
+
+		// If Error is not nil, ExecRaw will return a nil sql.Result and
+		// this Error.
+		Error error
+	}
+
+	// StrictResult is a sql.Result that fails when one of its methods
+	// that wasn't configured is called. It surfaces code that reads a
+	// result value the test didn't intend to provide.
+	StrictResult struct {
+		// LastInsertIdResult and RowsAffectedResult are the values
+		// returned by the LastInsertId and RowsAffected methods. If one
+		// is nil, calling its method fails.
+		LastInsertIdResult *ResultValue
+		RowsAffectedResult *ResultValue
+
+		// If FailWithT is not nil, an unexpected call fails the test
+		// with FailWithT.Fatalf instead of panicking.
+		FailWithT testing.TB
+	}
+
+	// ResultValue is a value returned by a StrictResult method.
+	ResultValue struct {
+		Value int64
+		Error error
 	}
 
 	MockQueryResult struct {
@@ -81,17 +287,61 @@ type (
 	}
 )
 
-func (MockExecOperation) doNothing()   {}
-func (MockScanOperation) doNothing()   {}
-func (MockExistsOperation) doNothing() {}
+func (MockExecOperation) doNothing()         {}
+func (MockScanOperation) doNothing()         {}
+func (MockExistsOperation) doNothing()       {}
+func (MockCountOperation) doNothing()        {}
+func (MockScanAndCountOperation) doNothing() {}
+func (MockRawScanOperation) doNothing()      {}
+func (MockRawExecOperation) doNothing()      {}
+
+func (op MockExecOperation) name() string         { return op.Name }
+func (op MockScanOperation) name() string         { return op.Name }
+func (op MockExistsOperation) name() string       { return op.Name }
+func (op MockCountOperation) name() string        { return op.Name }
+func (op MockScanAndCountOperation) name() string { return op.Name }
+func (op MockRawScanOperation) name() string      { return op.Name }
+func (op MockRawExecOperation) name() string      { return op.Name }
 
 // Creates a *bun.DB with a mocked database.
 func NewMockedBunDB() (*bun.DB, error) {
-	sqldb, _, err := sqlmock.New()
+	return NewMockedBunDBWithDialect(sqlitedialect.New())
+}
+
+// Creates a *bun.DB with a mocked database that uses the dialect d, so
+// the SQL generated for it matches the production database's.
+func NewMockedBunDBWithDialect(d schema.Dialect) (*bun.DB, error) {
+	db, _, err := newMockedBunDB(d)
+	return db, err
+}
+
+// Creates a *bun.DB with a mocked database and returns the sqlmock
+// handle of the database, so driver-level expectations can be set, e.g.
+// to test a QueryRealizer against the SQL bun generates.
+func NewMockedBunDBWithMock() (*bun.DB, sqlmock.Sqlmock, error) {
+	return newMockedBunDB(sqlitedialect.New())
+}
+
+// Creates a Bunoffe over a mocked database whose Executor is a
+// MockQueryExecutor with the given operations. It returns the executor
+// too, to inspect it after the calls. For instance:
+//
+//	b, ex, err := bunoffe.NewMockBunoffe(bunoffe.MockScanOperation{Model: &u})
+func NewMockBunoffe(ops ...MockedQueryOperation) (Bunoffe, *MockQueryExecutor, error) {
+	db, err := NewMockedBunDB()
 	if err != nil {
-		return nil, err
+		return Bunoffe{}, nil, err
+	}
+	ex := &MockQueryExecutor{Ops: ops}
+	return Bunoffe{X: ex, DB: db}, ex, nil
+}
+
+func newMockedBunDB(d schema.Dialect) (*bun.DB, sqlmock.Sqlmock, error) {
+	sqldb, mock, err := sqlmock.New()
+	if err != nil {
+		return nil, nil, err
 	}
-	return bun.NewDB(sqldb, sqlitedialect.New()), nil
+	return bun.NewDB(sqldb, d), mock, nil
 }
 
 // Exec mocks a query.Exec call. See the MockExecOperation documentation for details.
@@ -100,10 +350,42 @@ func (ex *MockQueryExecutor) Exec(
 	q ExecQuery,
 	args ...any,
 ) (sql.Result, error) {
-	nop := ex.nextOp()
-	op, ok := nop.(MockExecOperation)
+	ex.record("Exec", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return nil, err
+	}
+
+	op, ok := nextOpAs[MockExecOperation](ex, "MockExec")
 	if !ok {
-		panic(opCastError("MockExec", nop))
+		return nil, nil
+	}
+	if err := ex.delay(ctx, op.Delay); err != nil {
+		return nil, err
+	}
+
+	if op.ExpectSQL != nil && !ex.checkSQL(op, op.ExpectSQL, q) {
+		return nil, nil
+	}
+	if op.ExpectArgs != nil && !equalArgs(op.ExpectArgs, args) {
+		ex.fail(fmt.Sprintf(
+			"operation %v: expected args %v, but found %v",
+			opLabel(ex.cur, op),
+			op.ExpectArgs,
+			args,
+		))
+		return nil, nil
+	}
+	if op.ExpectPK != nil && !ex.checkPK(op, op.ExpectPK, q.GetModel()) {
+		return nil, nil
+	}
+
+	if op.Do != nil {
+		return op.Do(q, args)
+	}
+
+	if !ex.checkModelType(op, "Model", op.Model, q.GetModel(), false) ||
+		!ex.checkModelType(op, "Returning", op.Returning, q.GetModel(), true) {
+		return nil, nil
 	}
 
 	if op.Error != nil {
@@ -113,55 +395,87 @@ func (ex *MockQueryExecutor) Exec(
 	if op.Model != nil {
 		assign(
 			reflect.ValueOf(op.Model),
-			reflect.ValueOf(q.GetModel().Value()),
+			modelValue(q.GetModel()),
 		)
 	}
-
-	if len(op.Args) > 0 && len(op.Args) != len(args) {
-		panic("operation.Args and args should have the same length")
-	}
-	for i, val := range op.Args {
+	if op.Returning != nil {
 		assign(
-			reflect.ValueOf(args[i]),
-			reflect.ValueOf(val),
+			modelValue(q.GetModel()),
+			reflect.ValueOf(op.Returning),
 		)
 	}
+
+	if !ex.assignArgs(op, "Args", args, op.Args) {
+		return nil, nil
+	}
 	return op.Result, nil
 }
 
 // Exec mocks a query.Scan call. See the MockScanOperation documentation for details.
 func (ex *MockQueryExecutor) Scan(ctx context.Context, q ScanQuery, args ...any) error {
-	nop := ex.nextOp()
-	op, ok := nop.(MockScanOperation)
+	ex.record("Scan", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return err
+	}
+
+	op, ok := nextOpAs[MockScanOperation](ex, "MockScan")
 	if !ok {
-		panic(opCastError("MockScan", nop))
+		return nil
+	}
+	if err := ex.delay(ctx, op.Delay); err != nil {
+		return err
+	}
+
+	if op.ExpectPK != nil && !ex.checkPK(op, op.ExpectPK, q.GetModel()) {
+		return nil
+	}
+
+	if !ex.checkModelType(op, "Model", op.Model, q.GetModel(), true) {
+		return nil
 	}
 
 	if op.Error != nil {
 		return op.Error
 	}
 
-	if op.Model != nil {
-		assign(
-			reflect.ValueOf(q.GetModel().Value()),
-			reflect.ValueOf(op.Model),
-		)
+	model := op.Model
+	if op.Produce != nil {
+		model = op.Produce(q)
+		if !ex.checkModelType(op, "Produce result", model, q.GetModel(), true) {
+			return nil
+		}
 	}
-	for i, val := range op.Args {
+
+	if model != nil {
 		assign(
-			reflect.ValueOf(args[i]),
-			reflect.ValueOf(val),
+			modelValue(q.GetModel()),
+			reflect.ValueOf(model),
 		)
 	}
+
+	if !ex.assignArgs(op, "Args", args, op.Args) {
+		return nil
+	}
 	return nil
 }
 
 // Exec mocks a query.Exists call. See the MockExistsOperation documentation for details.
 func (ex *MockQueryExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
-	nop := ex.nextOp()
-	op, ok := nop.(MockExistsOperation)
+	ex.record("Exists", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return false, err
+	}
+
+	op, ok := nextOpAs[MockExistsOperation](ex, "MockExists")
 	if !ok {
-		panic(opCastError("MockExists", nop))
+		return false, nil
+	}
+	if err := ex.delay(ctx, op.Delay); err != nil {
+		return false, err
+	}
+
+	if op.ExpectModel != nil && !ex.checkModel(op, op.ExpectModel, q.GetModel()) {
+		return false, nil
 	}
 
 	if op.Error != nil {
@@ -170,18 +484,510 @@ func (ex *MockQueryExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, e
 	return op.Exists, nil
 }
 
-func (ex *MockQueryExecutor) nextOp() MockedQueryOperation {
+// Count mocks a query.Count call. See the MockCountOperation documentation for details.
+func (ex *MockQueryExecutor) Count(ctx context.Context, q CountQuery) (int, error) {
+	ex.record("Count", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return 0, err
+	}
+
+	op, ok := nextOpAs[MockCountOperation](ex, "MockCount")
+	if !ok {
+		return 0, nil
+	}
+
+	if op.Error != nil {
+		return 0, op.Error
+	}
+	return op.Count, nil
+}
+
+// ScanAndCount mocks a query.ScanAndCount call. See the
+// MockScanAndCountOperation documentation for details.
+func (ex *MockQueryExecutor) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	ex.record("ScanAndCount", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return 0, err
+	}
+
+	op, ok := nextOpAs[MockScanAndCountOperation](ex, "MockScanAndCount")
+	if !ok {
+		return 0, nil
+	}
+
+	if !ex.checkModelType(op, "Model", op.Model, q.GetModel(), true) {
+		return 0, nil
+	}
+
+	if op.Error != nil {
+		return 0, op.Error
+	}
+
+	if op.Model != nil {
+		assign(
+			modelValue(q.GetModel()),
+			reflect.ValueOf(op.Model),
+		)
+	}
+
+	if !ex.assignArgs(op, "Args", args, op.Args) {
+		return 0, nil
+	}
+	return op.Count, nil
+}
+
+// ScanRaw mocks a raw query.Scan call. See the MockRawScanOperation
+// documentation for details.
+func (ex *MockQueryExecutor) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	ex.record("ScanRaw", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return err
+	}
+
+	op, ok := nextOpAs[MockRawScanOperation](ex, "MockRawScan")
+	if !ok {
+		return nil
+	}
+
+	if op.Error != nil {
+		return op.Error
+	}
+
+	if !ex.assignArgs(op, "Dest", dest, op.Dest) {
+		return nil
+	}
+	return nil
+}
+
+// ExecRaw mocks a raw query.Exec call. See the MockRawExecOperation
+// documentation for details.
+func (ex *MockQueryExecutor) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	ex.record("ExecRaw", q)
+	if err := ex.ctxErr(ctx); err != nil {
+		return nil, err
+	}
+
+	op, ok := nextOpAs[MockRawExecOperation](ex, "MockRawExec")
+	if !ok {
+		return nil, nil
+	}
+
+	if op.Error != nil {
+		return nil, op.Error
+	}
+	return op.Result, nil
+}
+
+// RunInTx mocks a transaction. It calls f with the executor itself and
+// returns its error, if any. Otherwise, it returns CommitError. db is
+// ignored.
+func (ex *MockQueryExecutor) RunInTx(
+	ctx context.Context,
+	db bun.IDB,
+	f func(ctx context.Context, x Executor) error,
+) error {
+	if err := f(ctx, ex); err != nil {
+		return err
+	}
+	return ex.CommitError
+}
+
+// CallLog returns the names of the Executor methods called so far
+// ("Exec", "Scan", "Exists", "Count", "ScanAndCount", "ScanRaw", and
+// "ExecRaw"), in the order they were called.
+func (ex *MockQueryExecutor) CallLog() []string {
+	return append([]string(nil), ex.calls...)
+}
+
+// Reset rewinds the executor, so the operations in Ops are served again
+// from the first one, and clears the CallLog, call counters, and
+// recorded models.
+func (ex *MockQueryExecutor) Reset() {
+	ex.idx = 0
+	ex.unserved = 0
+	ex.consumed = nil
+	ex.calls = nil
+	ex.models = nil
+	ex.ExecCalls = 0
+	ex.ScanCalls = 0
+	ex.ExistsCalls = 0
+}
+
+// AssertCallCount fails the test if the number of consumed operations,
+// plus the calls that found no operation left, differs from
+// ExpectedCalls. Like AssertMinCalls, it doesn't count the calls
+// rejected because their context was done, which consume nothing. It
+// returns whether the assertion passed.
+func (ex *MockQueryExecutor) AssertCallCount(t testing.TB) bool {
+	t.Helper()
+	if n := ex.idx + ex.unserved; n != ex.ExpectedCalls {
+		t.Errorf(
+			"expected %v calls to the mocked executor, but found %v: %v",
+			ex.ExpectedCalls,
+			n,
+			ex.calls,
+		)
+		return false
+	}
+	return true
+}
+
+// AssertCallSequence fails the test if the Executor methods called so
+// far, as in CallLog, aren't want, in order, reporting the first call
+// that diverges. It returns whether the assertion passed. For instance:
+//
+//	ex.AssertCallSequence(t, "Exists", "Exec", "Scan")
+func (ex *MockQueryExecutor) AssertCallSequence(t testing.TB, want ...string) bool {
+	t.Helper()
+	for i := 0; i < len(want) || i < len(ex.calls); i++ {
+		var msg string
+		switch {
+		case len(ex.calls) <= i:
+			msg = fmt.Sprintf("expected %v, but there was no such call", want[i])
+		case len(want) <= i:
+			msg = fmt.Sprintf("expected no call, but found %v", ex.calls[i])
+		case want[i] != ex.calls[i]:
+			msg = fmt.Sprintf("expected %v, but found %v", want[i], ex.calls[i])
+		default:
+			continue
+		}
+		t.Errorf("call #%v to the mocked executor: %v: %v", i, msg, ex.calls)
+		return false
+	}
+	return true
+}
+
+// AssertMinCalls returns an error if fewer than n operations were
+// consumed. Unlike AssertCallCount, it allows Ops to end with optional
+// operations the code under test may not reach.
+func (ex *MockQueryExecutor) AssertMinCalls(n int) error {
+	if ex.idx < n {
+		return fmt.Errorf(
+			"expected at least %v operations to be consumed, but found %v",
+			n,
+			ex.idx,
+		)
+	}
+	return nil
+}
+
+// LastModel returns the value passed to the query method `.Model(&m)`
+// of the last call, or nil if there was no call or the query had no
+// model.
+func (ex *MockQueryExecutor) LastModel() any {
+	return ex.ModelAt(len(ex.models) - 1)
+}
+
+// ModelAt returns the value passed to the query method `.Model(&m)` of
+// the i-th call, starting at 0, or nil if there was no such call or the
+// query had no model.
+func (ex *MockQueryExecutor) ModelAt(i int) any {
+	if i < 0 || len(ex.models) <= i {
+		return nil
+	}
+	return ex.models[i]
+}
+
+// Dump renders the operations, one per line, with their index, type,
+// whether they were consumed or are pending, and the fields that are
+// set, e.g.:
+//
+//	#0 (load user) MockScanOperation consumed: Model
+//	#1 MockExecOperation pending: Error="an error"
+//
+// It helps to diagnose which operation a failing test was served.
+func (ex *MockQueryExecutor) Dump() string {
+	var sb strings.Builder
+	for i, op := range ex.Ops {
+		status := "pending"
+		if ex.isConsumed(i) {
+			status = "consumed"
+		}
+		fmt.Fprintf(&sb, "%v %v %v", opLabel(i, op), reflect.TypeOf(op).Name(), status)
+		if fields := opFields(op); len(fields) > 0 {
+			fmt.Fprintf(&sb, ": %v", strings.Join(fields, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// isConsumed reports whether the operation at index i was served.
+func (ex MockQueryExecutor) isConsumed(i int) bool {
+	if ex.Unordered {
+		return i < len(ex.consumed) && ex.consumed[i]
+	}
+	return i < ex.idx
+}
+
+// opFields describes the fields of op that are set, except Name.
+func opFields(op MockedQueryOperation) []string {
+	var fields []string
+	v := reflect.ValueOf(op)
+	for i := 0; i < v.NumField(); i++ {
+		f, name := v.Field(i), v.Type().Field(i).Name
+		switch {
+		case name == "Name" || f.IsZero():
+		case name == "Error":
+			fields = append(fields, fmt.Sprintf("Error=%q", f.Interface()))
+		case f.Kind() == reflect.Bool || f.Kind() == reflect.Int ||
+			f.Kind() == reflect.Int64:
+			fields = append(fields, fmt.Sprintf("%v=%v", name, f.Interface()))
+		default:
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// PeekOp returns the operation the next Executor method call will be
+// served, without consuming it, or false if no operation is left. If
+// Unordered is true, it returns the first pending operation.
+func (ex *MockQueryExecutor) PeekOp() (MockedQueryOperation, bool) {
+	i := ex.peekIndex()
+	if i < 0 {
+		return nil, false
+	}
+	return ex.Ops[i], true
+}
+
+// String summarizes the operations, marking the one PeekOp returns
+// with *. It has a value receiver, so both a MockQueryExecutor and a
+// pointer to one format with it, e.g.:
+//
+//	MockQueryExecutor(idx=2/5: [Scan, Exec, Exec*, Exists, Exec])
+func (ex MockQueryExecutor) String() string {
+	next := ex.peekIndex()
+	names := make([]string, len(ex.Ops))
+	for i, op := range ex.Ops {
+		name := reflect.TypeOf(op).Name()
+		names[i] = strings.TrimSuffix(strings.TrimPrefix(name, "Mock"), "Operation")
+		if i == next {
+			names[i] += "*"
+		}
+	}
+	return fmt.Sprintf(
+		"MockQueryExecutor(idx=%v/%v: [%v])",
+		ex.idx,
+		len(ex.Ops),
+		strings.Join(names, ", "),
+	)
+}
+
+// peekIndex returns the index of the operation PeekOp returns, or -1.
+func (ex MockQueryExecutor) peekIndex() int {
+	for i := range ex.Ops {
+		if !ex.isConsumed(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ex *MockQueryExecutor) record(
+	method string,
+	q interface{ GetModel() bun.Model },
+) {
+	var model any
+	if m := q.GetModel(); m != nil {
+		model = m.Value()
+	}
+	ex.calls = append(ex.calls, method)
+	ex.models = append(ex.models, model)
+
+	switch method {
+	case "Exec":
+		ex.ExecCalls++
+	case "Scan":
+		ex.ScanCalls++
+	case "Exists":
+		ex.ExistsCalls++
+	}
+}
+
+func (ex *MockQueryExecutor) ctxErr(ctx context.Context) error {
+	if ex.IgnoreContext {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// delay waits for d, returning the context's error if ctx is done
+// first, unless IgnoreContext is true.
+func (ex *MockQueryExecutor) delay(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	done := ctx.Done()
+	if ex.IgnoreContext {
+		done = nil
+	}
+
+	select {
+	case <-done:
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// nextOpAs returns the operation that serves the current call, which
+// must be a T. expected names T in failure messages.
+func nextOpAs[T MockedQueryOperation](
+	ex *MockQueryExecutor,
+	expected string,
+) (T, bool) {
+	var zero T
+	if ex.Unordered {
+		return nextUnorderedOpAs[T](ex, expected)
+	}
+
+	nop, ok := ex.nextOp()
+	if !ok {
+		return zero, false
+	}
+	op, ok := nop.(T)
+	if !ok {
+		ex.failWith(ErrOpMismatch, ex.opCastError(expected, nop))
+		return zero, false
+	}
+	return op, true
+}
+
+// nextUnorderedOpAs returns the first pending operation that is a T and
+// marks it as consumed.
+func nextUnorderedOpAs[T MockedQueryOperation](
+	ex *MockQueryExecutor,
+	expected string,
+) (T, bool) {
+	if len(ex.consumed) != len(ex.Ops) {
+		consumed := make([]bool, len(ex.Ops))
+		copy(consumed, ex.consumed)
+		ex.consumed = consumed
+	}
+
+	for i, nop := range ex.Ops {
+		if op, ok := nop.(T); ok && !ex.consumed[i] {
+			ex.consumed[i] = true
+			ex.cur = i
+			ex.idx++
+			return op, true
+		}
+	}
+
+	// Pending operations of other types make it a mismatch, not an
+	// exhausted mock.
+	var zero T
+	ex.unserved++
+	if i := ex.peekIndex(); i >= 0 {
+		ex.failWith(ErrOpMismatch, fmt.Sprintf(
+			"there's no pending '%v' operation left, but %v (%T) is pending",
+			expected,
+			opLabel(i, ex.Ops[i]),
+			ex.Ops[i],
+		))
+		return zero, false
+	}
+	ex.failWith(
+		ErrOpsExhausted,
+		fmt.Sprintf("there's no pending '%v' operation left", expected),
+	)
+	return zero, false
+}
+
+func (ex *MockQueryExecutor) nextOp() (MockedQueryOperation, bool) {
 	if len(ex.Ops) <= ex.idx {
-		s := fmt.Sprintf(
+		msg := fmt.Sprintf(
 			"mocked query requested operation #%v, but test only contains %v",
 			ex.idx,
 			len(ex.Ops),
 		)
-		panic(s)
+		if ex.idx > 0 {
+			msg += fmt.Sprintf(
+				"; the last one served was %v",
+				opLabel(ex.idx-1, ex.Ops[ex.idx-1]),
+			)
+		}
+		ex.unserved++
+		ex.failWith(ErrOpsExhausted, msg)
+		return nil, false
 	}
 
+	ex.cur = ex.idx
 	ex.idx++
-	return ex.Ops[ex.idx-1]
+	return ex.Ops[ex.cur], true
+}
+
+// fail panics with an error with msg, or, if FailWithT is set, fails
+// the test with it.
+func (ex *MockQueryExecutor) fail(msg string) {
+	if ex.FailWithT != nil {
+		ex.FailWithT.Helper()
+	}
+	ex.failWith(nil, msg)
+}
+
+// failWith is like fail, but the error it panics with wraps kind.
+func (ex *MockQueryExecutor) failWith(kind error, msg string) {
+	if ex.FailWithT != nil {
+		ex.FailWithT.Helper()
+		ex.FailWithT.Fatalf("%s", msg)
+		return
+	}
+	panic(mockError{msg: msg, kind: kind})
+}
+
+// mockError is the value MockQueryExecutor panics with. Its message is
+// the failure's, and it wraps the kind of failure, if any.
+type mockError struct {
+	msg  string
+	kind error
+}
+
+func (e mockError) Error() string { return e.msg }
+func (e mockError) Unwrap() error { return e.kind }
+
+// NewResult creates a MockQueryResult with the given values.
+func NewResult(lastInsertID, rowsAffected int64) sql.Result {
+	return MockQueryResult{
+		LastInsertIdValue: lastInsertID,
+		RowsAffectedValue: rowsAffected,
+	}
+}
+
+// NewResultErr creates a MockQueryResult whose RowsAffected method
+// returns err.
+func NewResultErr(err error) sql.Result {
+	return MockQueryResult{RowsAffectedError: err}
+}
+
+// AutoIncResult returns a function that creates a MockQueryResult each
+// time it's called, whose LastInsertId is start in the first call,
+// start+1 in the second, and so on. RowsAffected is always 1. It's
+// handy to mock a series of inserts:
+//
+//	next := bunoffe.AutoIncResult(1)
+//	ex := bunoffe.MockQueryExecutor{
+//		Ops: []bunoffe.MockedQueryOperation{
+//			bunoffe.MockExecOperation{Result: next()},
+//			bunoffe.MockExecOperation{Result: next()},
+//		},
+//	}
+func AutoIncResult(start int64) func() sql.Result {
+	id := start
+	return func() sql.Result {
+		r := NewResult(id, 1)
+		id++
+		return r
+	}
 }
 
 func (r MockQueryResult) LastInsertId() (int64, error) {
@@ -192,19 +998,379 @@ func (r MockQueryResult) RowsAffected() (int64, error) {
 	return r.RowsAffectedValue, r.RowsAffectedError
 }
 
-func opCastError(expected string, found any) string {
-	return fmt.Sprintf("expected '%v' operation, but found '%T'", expected, found)
+func (r StrictResult) LastInsertId() (int64, error) {
+	return r.value("LastInsertId", r.LastInsertIdResult)
+}
+
+func (r StrictResult) RowsAffected() (int64, error) {
+	return r.value("RowsAffected", r.RowsAffectedResult)
+}
+
+func (r StrictResult) value(method string, v *ResultValue) (int64, error) {
+	if v != nil {
+		return v.Value, v.Error
+	}
+
+	msg := fmt.Sprintf("unexpected call to StrictResult.%v", method)
+	if r.FailWithT != nil {
+		r.FailWithT.Helper()
+		r.FailWithT.Fatalf("%s", msg)
+		return 0, nil
+	}
+	panic(msg)
+}
+
+func (ex *MockQueryExecutor) opCastError(
+	expected string,
+	found MockedQueryOperation,
+) string {
+	return fmt.Sprintf(
+		"operation %v: expected '%v' operation, but found '%T'",
+		opLabel(ex.cur, found),
+		expected,
+		found,
+	)
+}
+
+func (ex *MockQueryExecutor) lengthError(
+	op MockedQueryOperation,
+	field string,
+	want, got int,
+) string {
+	return fmt.Sprintf(
+		"operation %v: operation.%v has %v values, but %v were passed",
+		opLabel(ex.cur, op),
+		field,
+		want,
+		got,
+	)
+}
+
+// opLabel identifies the operation at index i in failure messages, like
+// "#3 (insert user)", or "#3" if the operation has no name.
+func opLabel(i int, op MockedQueryOperation) string {
+	if op.name() == "" {
+		return fmt.Sprintf("#%v", i)
+	}
+	return fmt.Sprintf("#%v (%v)", i, op.name())
+}
+
+// checkModel fails if the query model m doesn't have the same type as
+// expected, ignoring pointers.
+func (ex *MockQueryExecutor) checkModel(
+	op MockedQueryOperation,
+	expected any,
+	m bun.Model,
+) bool {
+	want := reflect.TypeOf(expected)
+	got := modelValue(m)
+	if got.IsValid() && indirectType(got.Type()) == indirectType(want) {
+		return true
+	}
+
+	found := "no model"
+	if got.IsValid() {
+		found = got.Type().String()
+	}
+	ex.fail(fmt.Sprintf(
+		"operation %v: expected the query model to be a %v, but found %v",
+		opLabel(ex.cur, op),
+		want,
+		found,
+	))
+	return false
+}
+
+// checkModelType fails if the value v of the operation's field and the
+// query model m have incompatible types. If intoQuery is true, v is
+// assigned to the query model, otherwise the other way around.
+func (ex *MockQueryExecutor) checkModelType(
+	op MockedQueryOperation,
+	field string,
+	v any,
+	m bun.Model,
+	intoQuery bool,
+) bool {
+	ov, qv := reflect.ValueOf(v), modelValue(m)
+	if isNil(ov) || isNil(qv) {
+		return true
+	}
+
+	ot, qt := indirectType(ov.Type()), indirectType(qv.Type())
+	if (intoQuery && compatible(ot, qt)) || (!intoQuery && compatible(qt, ot)) {
+		return true
+	}
+
+	ex.fail(fmt.Sprintf(
+		"operation %v: mock %v is %v but query model is %v",
+		opLabel(ex.cur, op),
+		field,
+		ov.Type(),
+		qv.Type(),
+	))
+	return false
+}
+
+// equalArgs reports whether a and b are deeply equal, treating nil and
+// empty as equal, since args is nil when no args are passed.
+func equalArgs(a []any, b []any) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+// checkSQL fails if the SQL of the query q doesn't match re.
+func (ex *MockQueryExecutor) checkSQL(
+	op MockedQueryOperation,
+	re *regexp.Regexp,
+	q any,
+) bool {
+	query := querySQL(q)
+	if re.MatchString(query) {
+		return true
+	}
+
+	ex.fail(fmt.Sprintf(
+		"operation %v: expected the query to match %q, but found %q",
+		opLabel(ex.cur, op),
+		re,
+		query,
+	))
+	return false
+}
+
+// checkPK fails if the single primary key of the struct model m isn't
+// pk, converted to the type of the key.
+func (ex *MockQueryExecutor) checkPK(
+	op MockedQueryOperation,
+	pk any,
+	m bun.Model,
+) bool {
+	tm, ok := m.(interface{ Table() *schema.Table })
+	mv := modelValue(m)
+	v := reflect.Indirect(mv)
+	if !ok || v.Kind() != reflect.Struct || len(tm.Table().PKs) != 1 {
+		found := "no model"
+		if mv.IsValid() {
+			found = mv.Type().String()
+		}
+		ex.fail(fmt.Sprintf(
+			"operation %v: ExpectPK requires a struct model with a single primary key, but found %v",
+			opLabel(ex.cur, op),
+			found,
+		))
+		return false
+	}
+
+	fv := tm.Table().PKs[0].Value(v)
+	pv := reflect.ValueOf(pk)
+	if convertible(pv.Type(), fv.Type()) &&
+		reflect.DeepEqual(pv.Convert(fv.Type()).Interface(), fv.Interface()) {
+		return true
+	}
+
+	ex.fail(fmt.Sprintf(
+		"operation %v: expected primary key %v, but found %v",
+		opLabel(ex.cur, op),
+		pk,
+		fv,
+	))
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// modelValue returns the value passed to the query method `.Model(&m)`,
+// or an invalid value if the query has no model.
+func modelValue(m bun.Model) reflect.Value {
+	if m == nil {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(m.Value())
+}
+
+// assignArgs assigns each value of src, the field of op, to the
+// destination in dest with the same index. Nil values and destinations
+// are skipped. It fails if src isn't empty and the lengths differ, or if
+// a value can't be assigned to its destination.
+func (ex *MockQueryExecutor) assignArgs(
+	op MockedQueryOperation,
+	field string,
+	dest []any,
+	src []any,
+) bool {
+	if len(src) > 0 && len(src) != len(dest) {
+		ex.fail(ex.lengthError(op, field, len(src), len(dest)))
+		return false
+	}
+
+	for i, val := range src {
+		dv, sv := reflect.ValueOf(dest[i]), reflect.ValueOf(val)
+		if isNil(dv) || isNil(sv) {
+			continue
+		}
+		if !compatible(indirectOnce(sv.Type()), indirectOnce(dv.Type())) {
+			ex.fail(fmt.Sprintf(
+				"operation %v: operation.%v[%v] is %v, but the destination is %v",
+				opLabel(ex.cur, op),
+				field,
+				i,
+				sv.Type(),
+				dv.Type(),
+			))
+			return false
+		}
+	}
+
+	for i, val := range src {
+		assign(reflect.ValueOf(dest[i]), reflect.ValueOf(val))
+	}
+	return true
+}
+
+// indirectOnce returns the element type of t if it's a pointer, the
+// way assign dereferences its values.
+func indirectOnce(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
 }
 
 func assign(dest reflect.Value, src reflect.Value) {
+	// There's nothing to assign from or to nil pointers.
+	if isNil(dest) || isNil(src) {
+		return
+	}
+
+	dt, st := dest.Type(), src.Type()
+	if dest.Kind() == reflect.Ptr {
+		dest = dest.Elem()
+	}
+	if src.Kind() == reflect.Ptr {
+		src = src.Elem()
+	}
+	if !compatible(src.Type(), dest.Type()) {
+		panic(fmt.Sprintf("cannot assign %v into %v", st, dt))
+	}
+
+	// Slices are copied element by element, so the caller's slice doesn't
+	// share its backing array with the operation's. If both have the same
+	// length, the elements are copied in place.
+	if dest.Kind() == reflect.Slice && src.Kind() == reflect.Slice {
+		switch {
+		case src.IsNil():
+			dest.Set(reflect.Zero(dest.Type()))
+		case dest.Len() == src.Len() && !dest.IsNil():
+			copySlice(dest, src)
+		default:
+			s := reflect.MakeSlice(dest.Type(), src.Len(), src.Len())
+			copySlice(s, src)
+			dest.Set(s)
+		}
+		return
+	}
+
+	// Maps are copied entry by entry into the caller's map, which is
+	// allocated if nil.
+	if dest.Kind() == reflect.Map && src.Kind() == reflect.Map {
+		if dest.IsNil() {
+			dest.Set(reflect.MakeMapWithSize(dest.Type(), src.Len()))
+		}
+		for it := src.MapRange(); it.Next(); {
+			dest.SetMapIndex(
+				converted(it.Key(), dest.Type().Key()),
+				converted(it.Value(), dest.Type().Elem()),
+			)
+		}
+		return
+	}
+	set(dest, src)
+}
+
+func isNil(v reflect.Value) bool {
+	return !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil())
+}
+
+func copySlice(dest reflect.Value, src reflect.Value) {
+	for i := 0; i < src.Len(); i++ {
+		set(dest.Index(i), src.Index(i))
+	}
+}
+
+// set sets dest to a clone of src, converting it to the type of dest if
+// they're convertible but not assignable, e.g. an int64 into an int.
+func set(dest reflect.Value, src reflect.Value) {
+	dest.Set(converted(clone(src), dest.Type()))
+}
+
+// clone returns a copy of v that shares no slices or maps with it, even
+// within its struct fields, e.g. the slice of a has-many relation.
+// Pointers and unexported fields are copied as they are.
+func clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < c.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(clone(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(clone(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), clone(it.Value()))
+		}
+		return c
+	}
+	return v
+}
+
+// converted returns v as a value assignable to t.
+func converted(v reflect.Value, t reflect.Type) reflect.Value {
+	switch vt := v.Type(); {
+	case vt.AssignableTo(t):
+		return v
+	case convertible(vt, t):
+		return v.Convert(t)
+	default:
+		panic(fmt.Sprintf("cannot assign %v into %v", vt, t))
+	}
+}
+
+// compatible reports whether assign can copy a src into a dest.
+func compatible(src reflect.Type, dest reflect.Type) bool {
 	switch {
-	case dest.Kind() == reflect.Ptr && src.Kind() == reflect.Ptr:
-		dest.Elem().Set(src.Elem())
-	case dest.Kind() == reflect.Ptr && src.Kind() != reflect.Ptr:
-		dest.Elem().Set(src)
-	case dest.Kind() != reflect.Ptr && src.Kind() == reflect.Ptr:
-		dest.Set(src.Elem())
-	case dest.Kind() != reflect.Ptr && src.Kind() != reflect.Ptr:
-		dest.Set(src.Elem())
+	case src.Kind() == reflect.Slice && dest.Kind() == reflect.Slice:
+		return compatible(src.Elem(), dest.Elem())
+	case src.Kind() == reflect.Map && dest.Kind() == reflect.Map:
+		return compatible(src.Key(), dest.Key()) &&
+			compatible(src.Elem(), dest.Elem())
+	}
+	return src.AssignableTo(dest) || convertible(src, dest)
+}
+
+func convertible(src reflect.Type, dest reflect.Type) bool {
+	// Converting an integer to a string yields a rune, not its digits.
+	if dest.Kind() == reflect.String && src.Kind() != reflect.String {
+		return false
 	}
+	return src.ConvertibleTo(dest)
 }
@@ -0,0 +1,75 @@
+package bunoffe
+
+// MockExecutorBuilder assembles the operations of a MockQueryExecutor,
+// so they read in the order the Executor methods are expected to be
+// called. For instance:
+//
+//	ex := bunoffe.NewMockExecutor().
+//		OnExists(false).
+//		OnExec(bunoffe.MockExecOperation{Result: result}).
+//		Build()
+type MockExecutorBuilder struct {
+	ops []MockedQueryOperation
+}
+
+// NewMockExecutor creates an empty MockExecutorBuilder.
+func NewMockExecutor() *MockExecutorBuilder {
+	return &MockExecutorBuilder{}
+}
+
+// On appends any operation.
+func (b *MockExecutorBuilder) On(op MockedQueryOperation) *MockExecutorBuilder {
+	b.ops = append(b.ops, op)
+	return b
+}
+
+// OnExec appends an operation that serves an Exec call.
+func (b *MockExecutorBuilder) OnExec(op MockExecOperation) *MockExecutorBuilder {
+	return b.On(op)
+}
+
+// OnScan appends an operation that serves a Scan call.
+func (b *MockExecutorBuilder) OnScan(op MockScanOperation) *MockExecutorBuilder {
+	return b.On(op)
+}
+
+// OnExists appends an operation that makes an Exists call return
+// exists.
+func (b *MockExecutorBuilder) OnExists(exists bool) *MockExecutorBuilder {
+	return b.On(MockExistsOperation{Exists: exists})
+}
+
+// ExecError appends an operation that makes an Exec call return err.
+func (b *MockExecutorBuilder) ExecError(err error) *MockExecutorBuilder {
+	return b.On(MockExecOperation{Error: err})
+}
+
+// ScanModel appends an operation that assigns model to the query model
+// of a Scan call.
+func (b *MockExecutorBuilder) ScanModel(model any) *MockExecutorBuilder {
+	return b.On(MockScanOperation{Model: model})
+}
+
+// ScanError appends an operation that makes a Scan call return err.
+func (b *MockExecutorBuilder) ScanError(err error) *MockExecutorBuilder {
+	return b.On(MockScanOperation{Error: err})
+}
+
+// ExistsTrue appends an operation that makes an Exists call return
+// true.
+func (b *MockExecutorBuilder) ExistsTrue() *MockExecutorBuilder {
+	return b.OnExists(true)
+}
+
+// ExistsFalse appends an operation that makes an Exists call return
+// false.
+func (b *MockExecutorBuilder) ExistsFalse() *MockExecutorBuilder {
+	return b.OnExists(false)
+}
+
+// Build creates a MockQueryExecutor with the appended operations.
+func (b *MockExecutorBuilder) Build() *MockQueryExecutor {
+	ops := make([]MockedQueryOperation, len(b.ops))
+	copy(ops, b.ops)
+	return &MockQueryExecutor{Ops: ops}
+}
@@ -0,0 +1,63 @@
+package bunoffe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockExecutorBuilder(t *testing.T) {
+	// expected
+	result := MockQueryResult{RowsAffectedValue: 1}
+
+	b := NewMockExecutor().
+		OnExists(true).
+		OnExec(MockExecOperation{Result: result}).
+		OnScan(MockScanOperation{}).
+		On(MockCountOperation{Count: 3})
+
+	ex := b.Build()
+	assert.Equal(
+		t,
+		[]MockedQueryOperation{
+			MockExistsOperation{Exists: true},
+			MockExecOperation{Result: result},
+			MockScanOperation{},
+			MockCountOperation{Count: 3},
+		},
+		ex.Ops,
+	)
+
+	// Executors built by the same builder don't share operations.
+	b.OnExists(false)
+	assert.Len(t, ex.Ops, 4)
+	assert.Len(t, b.Build().Ops, 5)
+}
+
+func TestMockExecutorBuilderShortcuts(t *testing.T) {
+	// expected
+	var (
+		err = errors.New("an error")
+		m   = model{String: "Hello, world!", Int: 33}
+	)
+
+	ex := NewMockExecutor().
+		ExistsTrue().
+		ExistsFalse().
+		ExecError(err).
+		ScanModel(&m).
+		ScanError(err).
+		Build()
+	assert.Equal(
+		t,
+		[]MockedQueryOperation{
+			MockExistsOperation{Exists: true},
+			MockExistsOperation{Exists: false},
+			MockExecOperation{Error: err},
+			MockScanOperation{Model: &m},
+			MockScanOperation{Error: err},
+		},
+		ex.Ops,
+	)
+}
@@ -0,0 +1,47 @@
+package bunoffe
+
+import (
+	"fmt"
+	"sync"
+)
+
+var scenarios = struct {
+	sync.Mutex
+	ops map[string][]MockedQueryOperation
+}{ops: map[string][]MockedQueryOperation{}}
+
+// RegisterScenario registers a sequence of operations under name, so
+// test files can share it through LoadScenario. It's meant to be
+// called from init functions or TestMain, and it panics if name is
+// already registered. For instance:
+//
+//	func init() {
+//		bunoffe.RegisterScenario(
+//			"signup",
+//			bunoffe.MockExistsOperation{Exists: false},
+//			bunoffe.MockExecOperation{Result: bunoffe.NewResult(1, 1)},
+//		)
+//	}
+func RegisterScenario(name string, ops ...MockedQueryOperation) {
+	scenarios.Lock()
+	defer scenarios.Unlock()
+
+	if _, ok := scenarios.ops[name]; ok {
+		panic(fmt.Sprintf("bunoffe: scenario %q is already registered", name))
+	}
+	scenarios.ops[name] = append([]MockedQueryOperation(nil), ops...)
+}
+
+// LoadScenario returns a copy of the operations registered under name,
+// ready to be used as the Ops of a MockQueryExecutor, or an error if
+// there's no such scenario.
+func LoadScenario(name string) ([]MockedQueryOperation, error) {
+	scenarios.Lock()
+	defer scenarios.Unlock()
+
+	ops, ok := scenarios.ops[name]
+	if !ok {
+		return nil, fmt.Errorf("bunoffe: unknown scenario %q", name)
+	}
+	return append([]MockedQueryOperation(nil), ops...), nil
+}
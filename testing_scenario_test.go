@@ -0,0 +1,56 @@
+package bunoffe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scenarioModel = model{String: "Hello", Int: 1}
+
+func init() {
+	RegisterScenario(
+		"test scenario",
+		MockExistsOperation{Exists: true},
+		MockScanOperation{Model: &scenarioModel},
+	)
+}
+
+func TestScenario(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	assert.PanicsWithValue(
+		t,
+		`bunoffe: scenario "test scenario" is already registered`,
+		func() { RegisterScenario("test scenario") },
+	)
+
+	_, err = LoadScenario("unknown")
+	assert.EqualError(t, err, `bunoffe: unknown scenario "unknown"`)
+
+	ops, err := LoadScenario("test scenario")
+	require.Nil(t, err)
+
+	// Changing the loaded operations doesn't change the scenario.
+	ops[0] = MockExistsOperation{Exists: false}
+	ops, err = LoadScenario("test scenario")
+	require.Nil(t, err)
+
+	ex := MockQueryExecutor{Ops: ops}
+
+	// results
+	var n model
+
+	exists, err := ex.Exists(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	err = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, err)
+	assert.Equal(t, scenarioModel, n)
+}
@@ -0,0 +1,46 @@
+package bunoffe
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// ExpectSelect expects a SELECT query from table, as generated by bun,
+// on the database of mock, e.g. one created by NewMockedBunDBWithMock.
+// It's an alternative to MockQueryExecutor that tests the queries down
+// to the driver. For instance:
+//
+//	bunoffe.ExpectSelect(mock, "users").
+//		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John"))
+func ExpectSelect(mock sqlmock.Sqlmock, table string) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(tablePattern("SELECT .* FROM", table))
+}
+
+// ExpectInsert expects an INSERT query into table, as generated by bun.
+// Inserts with a RETURNING clause are queries, not Execs, so they must
+// be expected with mock.ExpectQuery instead.
+func ExpectInsert(mock sqlmock.Sqlmock, table string) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(tablePattern("INSERT INTO", table))
+}
+
+// ExpectUpdate expects an UPDATE query of table, as generated by bun.
+func ExpectUpdate(mock sqlmock.Sqlmock, table string) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(tablePattern("UPDATE", table))
+}
+
+// ExpectDelete expects a DELETE query from table, as generated by bun.
+func ExpectDelete(mock sqlmock.Sqlmock, table string) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(tablePattern("DELETE FROM", table))
+}
+
+// tablePattern matches a query that starts with prefix followed by
+// table, quoted the way any dialect quotes identifiers, or not at all.
+func tablePattern(prefix string, table string) string {
+	return fmt.Sprintf(
+		"^%v [\"`]?%v[\"`]?( |$)",
+		prefix,
+		regexp.QuoteMeta(table),
+	)
+}
@@ -0,0 +1,51 @@
+package bunoffe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqlmockExpectations(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := model{String: "Hello", Int: 1}
+
+	ExpectSelect(mock, "models").
+		WillReturnRows(sqlmock.NewRows([]string{"string", "int"}).AddRow(m.String, m.Int))
+	ExpectInsert(mock, "models").WillReturnResult(sqlmock.NewResult(1, 1))
+	ExpectUpdate(mock, "models").WillReturnResult(sqlmock.NewResult(0, 2))
+	ExpectDelete(mock, "models").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	b := Bunoffe{X: QueryRealizer{}, DB: db}
+
+	// results
+	var n model
+
+	err = b.ScanWhere(ctx, &n, "int = ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+
+	_, err = b.Insert(ctx, &n)
+	assert.Nil(t, err)
+
+	_, err = b.UpdateWhere(ctx, &n, "int = ?", []any{2}, "string = ?", "Hello")
+	assert.Nil(t, err)
+
+	c, err := rowsAffected(b.DeleteWhere(ctx, &n, "int = ?", 2))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// The table must match whole.
+	ExpectSelect(mock, "model")
+	err = b.ScanWhere(ctx, &n, "int = ?", 1)
+	assert.NotNil(t, err)
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
 )
 
 type model struct {
@@ -15,6 +19,22 @@ type model struct {
 	Int    int
 }
 
+// fakeT records the message passed to Fatalf instead of stopping the test.
+type fakeT struct {
+	testing.TB
+	msg string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.msg = fmt.Sprintf(format, args...)
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.msg = fmt.Sprintf(format, args...)
+}
+
 func TestMocks(t *testing.T) {
 	db, err := NewMockedBunDB()
 	require.Nil(t, err)
@@ -104,6 +124,7 @@ func TestMocks(t *testing.T) {
 				MockScanOperation{Error: err},
 				MockScanOperation{Model: &m},
 				MockScanOperation{Model: &m, Args: []any{message, pi}},
+				MockScanOperation{Args: []any{message, pi}},
 			},
 		}
 
@@ -147,6 +168,18 @@ func TestMocks(t *testing.T) {
 		assert.Equal(t, m, n)
 		assert.Equal(t, message, s)
 		assert.Equal(t, pi, f)
+
+		assert.PanicsWithError(
+			t,
+			"operation #4: operation.Args has 2 values, but 1 were passed",
+			func() {
+				ex.Scan(
+					ctx,
+					db.NewSelect().Model(&n),
+					&s,
+				)
+			},
+		)
 	})
 
 	t.Run("test exists", func(t *testing.T) {
@@ -199,4 +232,1346 @@ func TestMocks(t *testing.T) {
 		assert.Nil(t, e)
 		assert.False(t, f)
 	})
+
+	t.Run("test count", func(t *testing.T) {
+		// expected
+		err := errors.New("an error")
+		ex := MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{},
+				MockCountOperation{Error: err},
+				MockCountOperation{Count: 42},
+			},
+		}
+
+		// results
+		var (
+			n model
+			e error
+			c int
+		)
+
+		assert.Panics(t, func() {
+			ex.Count(
+				ctx,
+				db.NewSelect().Model(&n),
+			)
+		})
+
+		c, e = ex.Count(
+			ctx,
+			db.NewSelect().Model(&n),
+		)
+		assert.Equal(t, 0, c)
+		assert.NotNil(t, e)
+
+		c, e = ex.Count(
+			ctx,
+			db.NewSelect().Model(&n),
+		)
+		assert.Nil(t, e)
+		assert.Equal(t, 42, c)
+	})
+
+	t.Run("test scan and count", func(t *testing.T) {
+		// expected
+		var (
+			err = errors.New("an error")
+			m   = []model{{String: "Hello, world!", Int: 33}}
+		)
+
+		ex := MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockCountOperation{},
+				MockScanAndCountOperation{Error: err},
+				MockScanAndCountOperation{Model: &m, Count: 10},
+			},
+		}
+
+		// results
+		var (
+			n []model
+			e error
+			c int
+		)
+
+		assert.Panics(t, func() {
+			ex.ScanAndCount(
+				ctx,
+				db.NewSelect().Model(&n),
+			)
+		})
+
+		c, e = ex.ScanAndCount(
+			ctx,
+			db.NewSelect().Model(&n),
+		)
+		assert.Equal(t, 0, c)
+		assert.NotNil(t, e)
+		assert.Empty(t, n)
+
+		c, e = ex.ScanAndCount(
+			ctx,
+			db.NewSelect().Model(&n),
+		)
+		assert.Nil(t, e)
+		assert.Equal(t, 10, c)
+		assert.Equal(t, m, n)
+	})
+
+	t.Run("test scan and count args", func(t *testing.T) {
+		ex := MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockScanAndCountOperation{Args: []any{7}, Count: 1},
+				MockScanAndCountOperation{Args: []any{7, 8}},
+			},
+		}
+
+		// results
+		var (
+			n []model
+			x int
+		)
+
+		c, e := ex.ScanAndCount(ctx, db.NewSelect().Model(&n), &x)
+		assert.Nil(t, e)
+		assert.Equal(t, 1, c)
+		assert.Equal(t, 7, x)
+
+		assert.PanicsWithError(
+			t,
+			"operation #1: operation.Args has 2 values, but 1 were passed",
+			func() { ex.ScanAndCount(ctx, db.NewSelect().Model(&n), &x) },
+		)
+	})
+
+	t.Run("test raw", func(t *testing.T) {
+		// expected
+		var (
+			err    = errors.New("an error")
+			m      = []model{{String: "Hello, world!", Int: 33}}
+			result = MockQueryResult{RowsAffectedValue: 2}
+		)
+
+		ex := MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockScanOperation{},
+				MockRawScanOperation{Error: err},
+				MockRawScanOperation{Dest: []any{m, 1}},
+				MockRawExecOperation{Error: err},
+				MockRawExecOperation{Result: result},
+			},
+		}
+
+		// results
+		var (
+			n []model
+			c int
+			e error
+			r sql.Result
+		)
+
+		assert.Panics(t, func() {
+			ex.ScanRaw(ctx, db.NewRaw("SELECT * FROM models"), &n)
+		})
+
+		e = ex.ScanRaw(ctx, db.NewRaw("SELECT * FROM models"), &n, &c)
+		assert.NotNil(t, e)
+		assert.Empty(t, n)
+
+		e = ex.ScanRaw(ctx, db.NewRaw("SELECT * FROM models"), &n, &c)
+		assert.Nil(t, e)
+		assert.Equal(t, m, n)
+		assert.Equal(t, 1, c)
+
+		r, e = ex.ExecRaw(ctx, db.NewRaw("DELETE FROM models"))
+		assert.NotNil(t, e)
+		assert.Nil(t, r)
+
+		r, e = ex.ExecRaw(ctx, db.NewRaw("DELETE FROM models"))
+		assert.Nil(t, e)
+		assert.Equal(t, result, r)
+	})
+}
+
+func TestExistsExpectModel(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	type other struct {
+		ID int
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: true, ExpectModel: model{}},
+			MockExistsOperation{Exists: true, ExpectModel: (*model)(nil)},
+		},
+	}
+
+	var (
+		n model
+		o other
+	)
+
+	f, e := ex.Exists(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.True(t, f)
+
+	assert.PanicsWithError(
+		t,
+		"operation #1: expected the query model to be a *bunoffe.model, but found *bunoffe.other",
+		func() {
+			ex.Exists(ctx, db.NewSelect().Model(&o))
+		},
+	)
+}
+
+func TestExecDo(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	var (
+		fail   = errors.New("an error")
+		result = MockQueryResult{RowsAffectedValue: 1}
+	)
+
+	do := func(q ExecQuery, args []any) (sql.Result, error) {
+		m := q.GetModel().Value().(*model)
+		if m.Int < 0 {
+			return nil, fail
+		}
+		*(args[0].(*string)) = m.String
+		return result, nil
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Do: do, Error: errors.New("ignored")},
+			MockExecOperation{Do: do},
+		},
+	}
+
+	// results
+	var s string
+
+	r, e := ex.Exec(
+		ctx,
+		db.NewInsert().Model(&model{String: "hadouken"}),
+		&s,
+	)
+	assert.Nil(t, e)
+	assert.Equal(t, result, r)
+	assert.Equal(t, "hadouken", s)
+
+	r, e = ex.Exec(
+		ctx,
+		db.NewInsert().Model(&model{Int: -1}),
+		&s,
+	)
+	assert.Equal(t, fail, e)
+	assert.Nil(t, r)
+}
+
+func TestExecExpectSQL(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	re := regexp.MustCompile(`SET "string" = 'hadouken'`)
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{ExpectSQL: re},
+			MockExecOperation{ExpectSQL: re},
+		},
+	}
+
+	n := model{String: "hadouken"}
+	assert.NotPanics(t, func() {
+		ex.Exec(ctx, db.NewUpdate().Model(&n).Column("string").Where("int = 1"))
+	})
+
+	assert.PanicsWithError(
+		t,
+		`operation #1: expected the query to match "SET \"string\" = 'hadouken'", `+
+			`but found "DELETE FROM \"models\" AS \"model\" WHERE (int = 1)"`,
+		func() {
+			ex.Exec(ctx, db.NewDelete().Model(&n).Where("int = 1"))
+		},
+	)
+}
+
+func TestExecExpectArgs(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{ExpectArgs: []any{}},
+			MockExecOperation{ExpectArgs: []any{"a", 1}},
+			MockExecOperation{ExpectArgs: []any{"a", 1}},
+		},
+	}
+
+	var n model
+	assert.NotPanics(t, func() {
+		ex.Exec(ctx, db.NewInsert().Model(&n))
+		ex.Exec(ctx, db.NewInsert().Model(&n), "a", 1)
+	})
+	assert.PanicsWithError(
+		t,
+		"operation #2: expected args [a 1], but found [a 2]",
+		func() {
+			ex.Exec(ctx, db.NewInsert().Model(&n), "a", 2)
+		},
+	)
+}
+
+func TestExecNilArgs(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Args: []any{"a", "b", 3}},
+		},
+	}
+
+	var (
+		n model
+		s string
+		i int
+	)
+
+	assert.NotPanics(t, func() {
+		ex.Exec(
+			ctx,
+			db.NewInsert().Model(&n),
+			&s, (*string)(nil), &i,
+		)
+	})
+	assert.Equal(t, "a", s)
+	assert.Equal(t, 3, i)
+}
+
+func TestScanNilModel(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := model{String: "Hello, world!", Int: 33}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: (*model)(nil)},
+			MockScanOperation{Model: &m},
+		},
+	}
+
+	// results
+	var n model
+
+	assert.NotPanics(t, func() {
+		e := ex.Scan(ctx, db.NewSelect().Model(&n))
+		assert.Nil(t, e)
+	})
+	assert.Equal(t, model{}, n)
+
+	assert.NotPanics(t, func() {
+		e := ex.Scan(ctx, db.NewSelect().Model((*model)(nil)))
+		assert.Nil(t, e)
+	})
+}
+
+func TestScanMismatchedModel(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	type other struct {
+		ID int
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &other{ID: 1}},
+			MockScanOperation{Model: []other{{ID: 1}}},
+			MockExecOperation{Returning: &other{ID: 1}},
+			MockScanAndCountOperation{Model: []other{{ID: 1}}, Count: 1},
+		},
+	}
+
+	var (
+		n  model
+		ns []model
+	)
+
+	assert.PanicsWithError(
+		t,
+		"operation #0: mock Model is *bunoffe.other but query model is *bunoffe.model",
+		func() {
+			ex.Scan(ctx, db.NewSelect().Model(&n))
+		},
+	)
+	assert.PanicsWithError(
+		t,
+		"operation #1: mock Model is []bunoffe.other but query model is *[]bunoffe.model",
+		func() {
+			ex.Scan(ctx, db.NewSelect().Model(&ns))
+		},
+	)
+	assert.PanicsWithError(
+		t,
+		"operation #2: mock Returning is *bunoffe.other but query model is *bunoffe.model",
+		func() {
+			ex.Exec(ctx, db.NewInsert().Model(&n))
+		},
+	)
+	assert.PanicsWithError(
+		t,
+		"operation #3: mock Model is []bunoffe.other but query model is *[]bunoffe.model",
+		func() {
+			ex.ScanAndCount(ctx, db.NewSelect().Model(&ns))
+		},
+	)
+}
+
+func TestConvertArgs(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Args: []any{int64(3), []int32{1, 2}}},
+			MockScanOperation{Args: []any{3}},
+		},
+	}
+
+	var (
+		n  model
+		i  int
+		is []int
+		s  string
+	)
+
+	e := ex.Scan(ctx, db.NewSelect().Model(&n), &i, &is)
+	assert.Nil(t, e)
+	assert.Equal(t, 3, i)
+	assert.Equal(t, []int{1, 2}, is)
+
+	assert.PanicsWithError(
+		t,
+		"operation #1: operation.Args[0] is int, but the destination is *string",
+		func() { ex.Scan(ctx, db.NewSelect().Model(&n), &s) },
+	)
+}
+
+func TestNilArgs(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Args: []any{nil, "b", nil}},
+			MockScanOperation{Args: []any{nil, 3}},
+		},
+	}
+
+	var (
+		n model
+		a = "a"
+		b string
+		c = 1
+	)
+
+	e := ex.Scan(ctx, db.NewSelect().Model(&n), &a, &b, &c)
+	assert.Nil(t, e)
+	assert.Equal(t, "a", a)
+	assert.Equal(t, "b", b)
+	assert.Equal(t, 1, c)
+
+	assert.PanicsWithError(
+		t,
+		"operation #1: operation.Args[1] is int, but the destination is *string",
+		func() { ex.Scan(ctx, db.NewSelect().Model(&n), &a, &b) },
+	)
+	assert.Equal(t, "a", a)
+}
+
+func TestCallLog(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{},
+			MockExecOperation{Error: errors.New("an error")},
+			MockScanOperation{},
+		},
+	}
+
+	var n model
+	ex.Exists(ctx, db.NewSelect().Model(&n))
+	ex.Exec(ctx, db.NewInsert().Model(&n))
+	ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Equal(t, []string{"Exists", "Exec", "Scan"}, ex.CallLog())
+	assert.Equal(t, 1, ex.ExistsCalls)
+	assert.Equal(t, 1, ex.ExecCalls)
+	assert.Equal(t, 1, ex.ScanCalls)
+
+	assert.Panics(t, func() {
+		ex.Scan(ctx, db.NewSelect().Model(&n))
+	})
+	assert.Equal(t, 2, ex.ScanCalls)
+
+	ex.Reset()
+	assert.Equal(t, 0, ex.ScanCalls)
+}
+
+func TestAssertCallCount(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{},
+			MockExecOperation{},
+		},
+		ExpectedCalls: 2,
+	}
+
+	var (
+		n  model
+		ft fakeT
+	)
+
+	ex.Exists(ctx, db.NewSelect().Model(&n))
+	assert.False(t, ex.AssertCallCount(&ft))
+	assert.Equal(
+		t,
+		"expected 2 calls to the mocked executor, but found 1: [Exists]",
+		ft.msg,
+	)
+
+	// A call with a done context consumes nothing, so it doesn't count.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	ex.Exec(cctx, db.NewInsert().Model(&n))
+	assert.False(t, ex.AssertCallCount(&ft))
+
+	ex.Exec(ctx, db.NewInsert().Model(&n))
+	assert.True(t, ex.AssertCallCount(t))
+
+	// A call that finds no operation left counts.
+	ex.FailWithT = &ft
+	ex.Exec(ctx, db.NewInsert().Model(&n))
+	assert.False(t, ex.AssertCallCount(&ft))
+	assert.Equal(
+		t,
+		"expected 2 calls to the mocked executor, but found 3: [Exists Exec Exec Exec]",
+		ft.msg,
+	)
+}
+
+func TestAssertCallSequence(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{},
+			MockExecOperation{},
+		},
+	}
+
+	var (
+		n  model
+		ft fakeT
+	)
+
+	ex.Exists(ctx, db.NewSelect().Model(&n))
+	ex.Exec(ctx, db.NewInsert().Model(&n))
+	assert.True(t, ex.AssertCallSequence(t, "Exists", "Exec"))
+
+	assert.False(t, ex.AssertCallSequence(&ft, "Exists", "Scan"))
+	assert.Equal(
+		t,
+		"call #1 to the mocked executor: expected Scan, but found Exec: [Exists Exec]",
+		ft.msg,
+	)
+
+	assert.False(t, ex.AssertCallSequence(&ft, "Exists", "Exec", "Scan"))
+	assert.Equal(
+		t,
+		"call #2 to the mocked executor: expected Scan, but there was no such call: [Exists Exec]",
+		ft.msg,
+	)
+
+	assert.False(t, ex.AssertCallSequence(&ft, "Exists"))
+	assert.Equal(
+		t,
+		"call #1 to the mocked executor: expected no call, but found Exec: [Exists Exec]",
+		ft.msg,
+	)
+}
+
+func TestAssertMinCalls(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{},
+			MockExecOperation{},
+			MockExecOperation{Name: "optional"},
+		},
+	}
+
+	var n model
+
+	ex.Exists(ctx, db.NewSelect().Model(&n))
+	err = ex.AssertMinCalls(2)
+	require.NotNil(t, err)
+	assert.Equal(t, "expected at least 2 operations to be consumed, but found 1", err.Error())
+
+	ex.Exec(ctx, db.NewInsert().Model(&n))
+	assert.Nil(t, ex.AssertMinCalls(2))
+}
+
+func TestRecordedModels(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{},
+			MockExecOperation{},
+			MockRawExecOperation{},
+		},
+	}
+
+	var (
+		n = model{String: "Hello"}
+		o = model{String: "World"}
+	)
+
+	assert.Nil(t, ex.LastModel())
+
+	ex.Exists(ctx, db.NewSelect().Model(&n))
+	ex.Exec(ctx, db.NewInsert().Model(&o))
+	assert.Same(t, &n, ex.ModelAt(0))
+	assert.Same(t, &o, ex.ModelAt(1))
+	assert.Same(t, &o, ex.LastModel())
+	assert.Nil(t, ex.ModelAt(2))
+
+	ex.ExecRaw(ctx, db.NewRaw("DELETE FROM models"))
+	assert.Nil(t, ex.LastModel())
+}
+
+func TestReset(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: true},
+		},
+	}
+
+	var n model
+	for i := 0; i < 3; i++ {
+		ex.Reset()
+
+		f, e := ex.Exists(ctx, db.NewSelect().Model(&n))
+		assert.Nil(t, e)
+		assert.True(t, f)
+		assert.Equal(t, []string{"Exists"}, ex.CallLog())
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{Result: MockQueryResult{}},
+		},
+	}
+
+	var n model
+
+	r, e := ex.Exec(ctx, db.NewInsert().Model(&n))
+	assert.ErrorIs(t, e, context.Canceled)
+	assert.Nil(t, r)
+
+	e = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.ErrorIs(t, e, context.Canceled)
+
+	f, e := ex.Exists(ctx, db.NewSelect().Model(&n))
+	assert.ErrorIs(t, e, context.Canceled)
+	assert.False(t, f)
+
+	// No operation was consumed.
+	ex.IgnoreContext = true
+	r, e = ex.Exec(ctx, db.NewInsert().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, MockQueryResult{}, r)
+}
+
+func TestUnordered(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := model{String: "Hello, world!", Int: 33}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: true},
+			MockScanOperation{Model: &m},
+			MockExistsOperation{Exists: false},
+		},
+		Unordered: true,
+	}
+
+	// results
+	var n model
+
+	e := ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, m, n)
+
+	f, e := ex.Exists(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.True(t, f)
+
+	f, e = ex.Exists(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.False(t, f)
+
+	assert.Panics(t, func() {
+		ex.Scan(ctx, db.NewSelect().Model(&n))
+	})
+}
+
+func TestNewMockedBunDBWithDialect(t *testing.T) {
+	d := sqlitedialect.New()
+
+	db, err := NewMockedBunDBWithDialect(d)
+	require.Nil(t, err)
+	assert.Same(t, d, db.Dialect())
+}
+
+func TestScanSlice(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := []model{
+		{String: "Hello", Int: 1},
+		{String: "World", Int: 2},
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+			MockScanOperation{Model: m},
+			MockScanOperation{Model: []model{}},
+			MockScanOperation{Model: m},
+		},
+	}
+
+	// results
+	var n []model
+
+	e := ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, m, n)
+
+	n[0].Int = 10
+	assert.Equal(t, 1, m[0].Int)
+
+	n = nil
+	e = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, m, n)
+
+	e = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.NotNil(t, n)
+	assert.Empty(t, n)
+
+	// Slices with the same length are copied in place.
+	n = make([]model, 2)
+	o := n
+	e = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, m, o)
+}
+
+func TestScanMap(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := map[string]any{"string": "Hello, world!", "int": 33}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: &m},
+			MockScanOperation{Model: m},
+		},
+	}
+
+	// results
+	var n map[string]any
+
+	e := ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, m, n)
+
+	n = map[string]any{}
+	e = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, m, n)
+}
+
+func TestScanMapEntries(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: map[string]int{"count": 3}},
+		},
+	}
+
+	// The entries are copied into the caller's map, keeping the others.
+	n := map[string]any{"name": "John"}
+	e := ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, e)
+	assert.Equal(t, map[string]any{"name": "John", "count": 3}, n)
+}
+
+func TestNewResult(t *testing.T) {
+	r := NewResult(10, 11)
+
+	id, err := r.LastInsertId()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), id)
+
+	n, err := r.RowsAffected()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), n)
+
+	e := errors.New("an error")
+	r = NewResultErr(e)
+
+	n, err = r.RowsAffected()
+	assert.Equal(t, e, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestStrictResult(t *testing.T) {
+	var ft fakeT
+
+	r := StrictResult{
+		RowsAffectedResult: &ResultValue{Value: 2},
+	}
+
+	n, err := r.RowsAffected()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+
+	assert.PanicsWithValue(t, "unexpected call to StrictResult.LastInsertId", func() {
+		r.LastInsertId()
+	})
+
+	r.FailWithT = &ft
+	r.LastInsertId()
+	assert.Equal(t, "unexpected call to StrictResult.LastInsertId", ft.msg)
+}
+
+func TestAutoIncResult(t *testing.T) {
+	next := AutoIncResult(10)
+
+	for i := int64(10); i < 13; i++ {
+		r := next()
+
+		id, err := r.LastInsertId()
+		assert.Nil(t, err)
+		assert.Equal(t, i, id)
+
+		n, err := r.RowsAffected()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+}
+
+func TestFailWithT(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	var (
+		n  model
+		ft fakeT
+	)
+
+	ex := MockQueryExecutor{
+		Ops:       []MockedQueryOperation{MockScanOperation{Name: "load user"}},
+		FailWithT: &ft,
+	}
+
+	assert.NotPanics(t, func() {
+		ex.Exec(ctx, db.NewInsert().Model(&n))
+	})
+	assert.Equal(
+		t,
+		"operation #0 (load user): expected 'MockExec' operation, but found 'bunoffe.MockScanOperation'",
+		ft.msg,
+	)
+
+	assert.NotPanics(t, func() {
+		ex.Exists(ctx, db.NewSelect().Model(&n))
+	})
+	assert.Equal(
+		t,
+		"mocked query requested operation #1, but test only contains 1; the last one served was #0 (load user)",
+		ft.msg,
+	)
+}
+
+func TestPeekOp(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	t.Run("test ordered", func(t *testing.T) {
+		ex := MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{Name: "first"},
+				MockScanOperation{Name: "second"},
+			},
+		}
+
+		op, ok := ex.PeekOp()
+		assert.True(t, ok)
+		assert.Equal(t, MockExistsOperation{Name: "first"}, op)
+
+		// peeking doesn't consume
+		op, ok = ex.PeekOp()
+		assert.True(t, ok)
+		assert.Equal(t, MockExistsOperation{Name: "first"}, op)
+
+		var n model
+		ex.Exists(ctx, db.NewSelect().Model(&n))
+		op, ok = ex.PeekOp()
+		assert.True(t, ok)
+		assert.Equal(t, MockScanOperation{Name: "second"}, op)
+
+		ex.Scan(ctx, db.NewSelect().Model(&n))
+		op, ok = ex.PeekOp()
+		assert.False(t, ok)
+		assert.Nil(t, op)
+	})
+
+	t.Run("test unordered", func(t *testing.T) {
+		ex := MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{Name: "first"},
+				MockScanOperation{Name: "second"},
+			},
+			Unordered: true,
+		}
+
+		var n model
+		ex.Scan(ctx, db.NewSelect().Model(&n))
+		op, ok := ex.PeekOp()
+		assert.True(t, ok)
+		assert.Equal(t, MockExistsOperation{Name: "first"}, op)
+
+		ex.Exists(ctx, db.NewSelect().Model(&n))
+		_, ok = ex.PeekOp()
+		assert.False(t, ok)
+	})
+}
+
+func TestScanProduce(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	produce := func(q ScanQuery) any {
+		m := q.GetModel().Value().(*model)
+		return model{String: "produced", Int: m.Int * 2}
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Produce: produce, Model: model{String: "ignored"}},
+			MockScanOperation{Produce: func(q ScanQuery) any { return 1 }},
+		},
+	}
+
+	// results
+	n := model{Int: 21}
+
+	err = ex.Scan(ctx, db.NewSelect().Model(&n))
+	assert.Nil(t, err)
+	assert.Equal(t, model{String: "produced", Int: 42}, n)
+
+	assert.PanicsWithError(
+		t,
+		"operation #1: mock Produce result is int but query model is *bunoffe.model",
+		func() { ex.Scan(ctx, db.NewSelect().Model(&n)) },
+	)
+}
+
+type (
+	article struct {
+		ID       int64 `bun:",pk"`
+		AuthorID int64
+		Author   *author `bun:"rel:belongs-to,join:author_id=id"`
+	}
+
+	author struct {
+		ID       int64 `bun:",pk"`
+		Name     string
+		Articles []article `bun:"rel:has-many,join:id=author_id"`
+	}
+)
+
+func TestScanRelation(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	// expected
+	m := author{
+		ID:       1,
+		Name:     "John",
+		Articles: []article{{ID: 1, AuthorID: 1}, {ID: 2, AuthorID: 1}},
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Model: m},
+			MockScanOperation{Model: &m},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		// results
+		var n author
+
+		err = ex.Scan(ctx, db.NewSelect().Model(&n).Relation("Articles"))
+		assert.Nil(t, err)
+		assert.Equal(t, m, n)
+
+		// the relation isn't shared with the operation
+		n.Articles[0].ID = 10
+		assert.Equal(t, int64(1), m.Articles[0].ID)
+	}
+}
+
+func TestExpectPK(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{ExpectPK: 5},
+			MockScanOperation{ExpectPK: int64(5)},
+			MockExecOperation{Name: "delete author", ExpectPK: 5},
+			MockScanOperation{ExpectPK: 5},
+		},
+	}
+
+	a := author{ID: 5}
+	assert.NotPanics(t, func() {
+		ex.Exec(ctx, db.NewDelete().Model(&a).WherePK())
+		ex.Scan(ctx, db.NewSelect().Model(&a).WherePK())
+	})
+
+	var b author
+	assert.PanicsWithError(
+		t,
+		"operation #2 (delete author): expected primary key 5, but found 0",
+		func() { ex.Exec(ctx, db.NewDelete().Model(&b).WherePK()) },
+	)
+
+	var m model
+	assert.PanicsWithError(
+		t,
+		"operation #3: ExpectPK requires a struct model with a single primary key, but found *bunoffe.model",
+		func() { ex.Scan(ctx, db.NewSelect().Model(&m)) },
+	)
+}
+
+// blob has a primary key that isn't comparable with ==.
+type blob struct {
+	Key []byte `bun:",pk"`
+}
+
+func TestExpectPKUncomparable(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExecOperation{ExpectPK: []byte("a")},
+			MockExecOperation{ExpectPK: []byte("a")},
+		},
+	}
+
+	a := blob{Key: []byte("a")}
+	assert.NotPanics(t, func() {
+		ex.Exec(ctx, db.NewDelete().Model(&a).WherePK())
+	})
+
+	b := blob{Key: []byte("b")}
+	assert.PanicsWithError(
+		t,
+		"operation #1: expected primary key [97], but found [98]",
+		func() { ex.Exec(ctx, db.NewDelete().Model(&b).WherePK()) },
+	)
+}
+
+func TestPanicErrors(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	recovered := func(f func()) (err error) {
+		defer func() {
+			err, _ = recover().(error)
+		}()
+		f()
+		return nil
+	}
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{MockScanOperation{}},
+	}
+
+	var m model
+	err = recovered(func() { ex.Exec(ctx, db.NewInsert().Model(&m)) })
+	assert.ErrorIs(t, err, ErrOpMismatch)
+	assert.NotErrorIs(t, err, ErrOpsExhausted)
+
+	err = recovered(func() { ex.Exec(ctx, db.NewInsert().Model(&m)) })
+	assert.ErrorIs(t, err, ErrOpsExhausted)
+	assert.NotErrorIs(t, err, ErrOpMismatch)
+
+	ex = MockQueryExecutor{Unordered: true}
+	err = recovered(func() { ex.Scan(ctx, db.NewSelect().Model(&m)) })
+	assert.ErrorIs(t, err, ErrOpsExhausted)
+
+	// An unordered executor with a pending operation of another type
+	// mismatches rather than runs out.
+	ex = MockQueryExecutor{
+		Unordered: true,
+		Ops:       []MockedQueryOperation{MockExecOperation{Name: "insert"}},
+	}
+	err = recovered(func() { ex.Scan(ctx, db.NewSelect().Model(&m)) })
+	assert.ErrorIs(t, err, ErrOpMismatch)
+	assert.NotErrorIs(t, err, ErrOpsExhausted)
+	assert.EqualError(
+		t,
+		err,
+		"there's no pending 'MockScan' operation left, but #0 (insert) (bunoffe.MockExecOperation) is pending",
+	)
+
+	ex.Exec(ctx, db.NewInsert().Model(&m))
+	err = recovered(func() { ex.Scan(ctx, db.NewSelect().Model(&m)) })
+	assert.ErrorIs(t, err, ErrOpsExhausted)
+	assert.NotErrorIs(t, err, ErrOpMismatch)
+}
+
+func TestDump(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Name: "load user", Model: &model{}},
+			MockExecOperation{Error: errors.New("an error")},
+			MockExistsOperation{Exists: true},
+		},
+	}
+
+	var m model
+	ex.Scan(ctx, db.NewSelect().Model(&m))
+
+	assert.Equal(
+		t,
+		"#0 (load user) MockScanOperation consumed: Model\n"+
+			"#1 MockExecOperation pending: Error=\"an error\"\n"+
+			"#2 MockExistsOperation pending: Exists=true\n",
+		ex.Dump(),
+	)
+}
+
+func TestNamedArgs(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{Args: []any{3}},
+		},
+	}
+
+	// results
+	var count int
+
+	q := db.WithNamedArg("min", 10).NewSelect().
+		Model((*model)(nil)).
+		ColumnExpr("count(*)").
+		Where("int > ?min AND string = ?", "a")
+
+	assert.NotPanics(t, func() {
+		err = ex.Scan(ctx, q, &count)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.Contains(t, querySQL(q), `WHERE (int > 10 AND string = 'a')`)
+}
+
+func TestNewMockBunoffe(t *testing.T) {
+	// expected
+	m := model{String: "Hello", Int: 1}
+
+	b, ex, err := NewMockBunoffe(MockScanOperation{Model: &m})
+	require.Nil(t, err)
+	assert.Same(t, ex, b.X)
+
+	// results
+	var n model
+
+	err = b.ScanWhere(context.Background(), &n, "int = ?", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, m, n)
+	assert.Equal(t, []string{"Scan"}, ex.CallLog())
+}
+
+func TestDelay(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ex := MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockExistsOperation{Exists: true, Delay: time.Millisecond},
+			MockScanOperation{Delay: time.Hour},
+			MockExecOperation{Delay: time.Hour},
+		},
+	}
+
+	var m model
+
+	exists, err := ex.Exists(context.Background(), db.NewSelect().Model(&m))
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = ex.Scan(ctx, db.NewSelect().Model(&m))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The context is already done, so the operation isn't consumed.
+	_, err = ex.Exec(ctx, db.NewInsert().Model(&m))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(
+		t,
+		"#0 MockExistsOperation consumed: Exists=true, Delay=1ms\n"+
+			"#1 MockScanOperation consumed: Delay=1h0m0s\n"+
+			"#2 MockExecOperation pending: Delay=1h0m0s\n",
+		ex.Dump(),
+	)
+}
+
+func TestString(t *testing.T) {
+	db, err := NewMockedBunDB()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	ex := &MockQueryExecutor{
+		Ops: []MockedQueryOperation{
+			MockScanOperation{},
+			MockExecOperation{},
+			MockRawExecOperation{},
+		},
+	}
+	assert.Equal(t, "MockQueryExecutor(idx=0/3: [Scan*, Exec, RawExec])", fmt.Sprintf("%v", ex))
+
+	var m model
+	ex.Scan(ctx, db.NewSelect().Model(&m))
+	assert.Equal(t, "MockQueryExecutor(idx=1/3: [Scan, Exec*, RawExec])", ex.String())
+
+	ex.Exec(ctx, db.NewInsert().Model(&m))
+	ex.ExecRaw(ctx, db.NewRaw("DELETE FROM models"))
+	assert.Equal(t, "MockQueryExecutor(idx=3/3: [Scan, Exec, RawExec])", ex.String())
+
+	// A value formats the same as a pointer.
+	assert.Equal(t, "MockQueryExecutor(idx=3/3: [Scan, Exec, RawExec])", fmt.Sprintf("%v", *ex))
+
+	v := MockQueryExecutor{Ops: []MockedQueryOperation{MockExistsOperation{}}}
+	assert.Equal(t, "MockQueryExecutor(idx=0/1: [Exists*])", fmt.Sprintf("%v", v))
 }
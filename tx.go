@@ -0,0 +1,150 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun"
+)
+
+// ErrUnboundQuery is returned by the TxExecutor methods when the query
+// has no Conn method that takes the transaction, so it would run
+// outside of it.
+var ErrUnboundQuery = errors.New("bunoffe: query can't be bound to the transaction")
+
+// TxRunner is the interface that wraps the method RunInTx, which runs
+// f in a transaction of db, committing it if f returns nil, or rolling
+// it back otherwise. The Executor passed to f runs the queries within
+// the transaction.
+//
+// Both QueryRealizer and MockQueryExecutor are TxRunners.
+type TxRunner interface {
+	RunInTx(
+		ctx context.Context,
+		db bun.IDB,
+		f func(ctx context.Context, x Executor) error,
+	) error
+}
+
+// TxExecutor is an Executor that runs the queries within the
+// transaction Tx, even if they were built against the database. That
+// is, calling
+//
+//	executor.Scan(ctx, db.NewSelect().Model(&m))
+//
+// is equivalent to running
+//
+//	db.NewSelect().Model(&m).Conn(tx).Scan(ctx)
+//
+// Queries built against the transaction itself already run within it,
+// so a Bunoffe{X: QueryRealizer{}, DB: tx} is transaction-scoped too.
+// Queries without a Conn method that takes the transaction fail with
+// ErrUnboundQuery instead of running outside of it.
+type TxExecutor struct {
+	Tx bun.Tx
+
+	// Realizer runs the queries once they're bound to Tx, so its hooks
+	// apply within the transaction too.
+	Realizer QueryRealizer
+}
+
+// NewTxExecutor creates an Executor that runs the queries within tx.
+func NewTxExecutor(tx bun.Tx) Executor {
+	return TxExecutor{Tx: tx}
+}
+
+// Exec executes the query within the transaction.
+func (ex TxExecutor) Exec(
+	ctx context.Context,
+	q ExecQuery,
+	args ...any,
+) (sql.Result, error) {
+	if err := ex.bind(q); err != nil {
+		return nil, err
+	}
+	return ex.Realizer.Exec(ctx, q, args...)
+}
+
+// Scan executes the query within the transaction.
+func (ex TxExecutor) Scan(ctx context.Context, q ScanQuery, args ...any) error {
+	if err := ex.bind(q); err != nil {
+		return err
+	}
+	return ex.Realizer.Scan(ctx, q, args...)
+}
+
+// Exists executes the query within the transaction.
+func (ex TxExecutor) Exists(ctx context.Context, q ExistsQuery) (bool, error) {
+	if err := ex.bind(q); err != nil {
+		return false, err
+	}
+	return ex.Realizer.Exists(ctx, q)
+}
+
+// Count executes the query within the transaction.
+func (ex TxExecutor) Count(ctx context.Context, q CountQuery) (int, error) {
+	if err := ex.bind(q); err != nil {
+		return 0, err
+	}
+	return ex.Realizer.Count(ctx, q)
+}
+
+// ScanAndCount executes the query within the transaction.
+func (ex TxExecutor) ScanAndCount(
+	ctx context.Context,
+	q ScanAndCountQuery,
+	args ...any,
+) (int, error) {
+	if err := ex.bind(q); err != nil {
+		return 0, err
+	}
+	return ex.Realizer.ScanAndCount(ctx, q, args...)
+}
+
+// ScanRaw executes the query within the transaction.
+func (ex TxExecutor) ScanRaw(ctx context.Context, q RawQuery, dest ...any) error {
+	if err := ex.bind(q); err != nil {
+		return err
+	}
+	return ex.Realizer.ScanRaw(ctx, q, dest...)
+}
+
+// ExecRaw executes the query within the transaction.
+func (ex TxExecutor) ExecRaw(ctx context.Context, q RawQuery) (sql.Result, error) {
+	if err := ex.bind(q); err != nil {
+		return nil, err
+	}
+	return ex.Realizer.ExecRaw(ctx, q)
+}
+
+// bind makes q run within the transaction. Every bun query has a Conn
+// method, but each returns its own type, so it's called via reflection.
+// It fails with ErrUnboundQuery if q has no such method.
+func (ex TxExecutor) bind(q any) error {
+	conn := reflect.ValueOf(q).MethodByName("Conn")
+	tx := reflect.ValueOf(ex.Tx)
+	if !conn.IsValid() ||
+		conn.Type().NumIn() != 1 ||
+		!tx.Type().AssignableTo(conn.Type().In(0)) {
+		return fmt.Errorf("%w: %T has no Conn(bun.IConn) method", ErrUnboundQuery, q)
+	}
+
+	conn.Call([]reflect.Value{tx})
+	return nil
+}
+
+// RunInTx runs f in a transaction of db using bun's RunInTx. The
+// Executor passed to f is a TxExecutor of the transaction that runs
+// the queries with r, hooks included.
+func (r QueryRealizer) RunInTx(
+	ctx context.Context,
+	db bun.IDB,
+	f func(ctx context.Context, x Executor) error,
+) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return f(ctx, TxExecutor{Tx: tx, Realizer: r})
+	})
+}
@@ -0,0 +1,163 @@
+package bunoffe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+func TestTxExecutor(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.Nil(t, err)
+	require.Nil(t, tx.Rollback())
+
+	// The query is built against the database, but runs within the
+	// transaction, which is done.
+	var m model
+	err = NewTxExecutor(tx).Scan(ctx, db.NewSelect().Model(&m))
+	assert.ErrorIs(t, err, sql.ErrTxDone)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+// connlessQuery is a ScanQuery without a Conn method.
+type connlessQuery struct {
+	scanned bool
+}
+
+func (q *connlessQuery) Scan(context.Context, ...any) error {
+	q.scanned = true
+	return nil
+}
+
+func (q *connlessQuery) GetModel() bun.Model {
+	return nil
+}
+
+func TestTxExecutorUnboundQuery(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.Nil(t, err)
+	defer tx.Rollback()
+
+	// The query can't be bound, so it doesn't run at all.
+	q := &connlessQuery{}
+	err = NewTxExecutor(tx).Scan(ctx, q)
+	assert.ErrorIs(t, err, ErrUnboundQuery)
+	assert.Contains(t, err.Error(), "*bunoffe.connlessQuery")
+	assert.False(t, q.scanned)
+}
+
+func TestRunInTx(t *testing.T) {
+	db, mock, err := NewMockedBunDBWithMock()
+	require.Nil(t, err)
+
+	ctx := context.Background()
+
+	t.Run("test realizer", func(t *testing.T) {
+		// expected
+		e := errors.New("an error")
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		var r TxRunner = QueryRealizer{}
+
+		err := r.RunInTx(ctx, db, func(ctx context.Context, x Executor) error {
+			assert.IsType(t, TxExecutor{}, x)
+			return nil
+		})
+		assert.Nil(t, err)
+
+		err = r.RunInTx(ctx, db, func(ctx context.Context, x Executor) error {
+			return e
+		})
+		assert.Equal(t, e, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test realizer hooks", func(t *testing.T) {
+		// expected
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT count`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+		mock.ExpectCommit()
+
+		// results
+		var before, after int
+
+		r := NewQueryRealizer(
+			WithBeforeHook(func(ctx context.Context, q any) { before++ }),
+			WithAfterHook(func(ctx context.Context, _ sql.Result, _ error) { after++ }),
+		)
+
+		err := r.RunInTx(ctx, db, func(ctx context.Context, x Executor) error {
+			var m model
+			count, err := x.Count(ctx, db.NewSelect().Model(&m))
+			assert.Equal(t, 4, count)
+			return err
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, before)
+		assert.Equal(t, 1, after)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test mock", func(t *testing.T) {
+		// expected
+		var (
+			e = errors.New("an error")
+			c = errors.New("commit error")
+		)
+
+		ex := &MockQueryExecutor{
+			Ops: []MockedQueryOperation{
+				MockExistsOperation{Exists: true},
+			},
+		}
+
+		var r TxRunner = ex
+
+		err := r.RunInTx(ctx, db, func(ctx context.Context, x Executor) error {
+			var m model
+			exists, err := x.Exists(ctx, db.NewSelect().Model(&m))
+			assert.Nil(t, err)
+			assert.True(t, exists)
+			return nil
+		})
+		assert.Nil(t, err)
+
+		err = r.RunInTx(ctx, db, func(ctx context.Context, x Executor) error {
+			return e
+		})
+		assert.Equal(t, e, err)
+
+		ex.CommitError = c
+		err = r.RunInTx(ctx, db, func(ctx context.Context, x Executor) error {
+			return nil
+		})
+		assert.Equal(t, c, err)
+	})
+}